@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package analyzer exposes the rules of checkapi as a go/analysis Analyzer, so that they can run
+// with go vet -vettool, as a golangci-lint plugin or in gopls.
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"go.opentelemetry.io/build-tools/checkapi/internal"
+)
+
+const doc = `check the API of OpenTelemetry Go modules
+
+The module of each package is checked against the rules of the checkapi
+configuration file set with -config. Diagnostics are reported on the
+declarations they apply to. Diagnostics about the whole module, or about its
+metadata.yaml file, are reported on the package in the folder of the module.`
+
+// Analyzer checks the module of each package against the rules of the configuration file set with its -config flag.
+var Analyzer = New("")
+
+// New returns an Analyzer checking the module of each package against the rules of the configuration file at configPath,
+// which can be overridden with its -config flag.
+func New(configPath string) *analysis.Analyzer {
+	c := &checker{configPath: configPath, modules: map[string]*moduleResult{}}
+	a := &analysis.Analyzer{
+		Name: "checkapi",
+		Doc:  doc,
+		URL:  "https://github.com/open-telemetry/opentelemetry-go-build-tools/tree/main/checkapi",
+		Run:  c.run,
+	}
+	a.Flags.StringVar(&c.configPath, "config", configPath, "configuration file of the rules")
+	a.Flags.StringVar(&c.versionsPath, "versions", "", "multimod versions.yaml file setting the version of the module set of each module")
+	a.Flags.StringVar(&c.cacheDir, "cache", "", "folder caching the API read for each module across runs")
+	return a
+}
+
+// checker checks modules, once for each content of their files, as the packages of a module share its diagnostics.
+type checker struct {
+	configPath   string
+	versionsPath string
+	cacheDir     string
+
+	mu      sync.Mutex
+	modules map[string]*moduleResult
+}
+
+// moduleResult holds the diagnostics of a module for the hash of its files and of the configuration.
+type moduleResult struct {
+	once  sync.Once
+	hash  string
+	diags []internal.Diagnostic
+	err   error
+}
+
+func (c *checker) run(pass *analysis.Pass) (any, error) {
+	if c.configPath == "" {
+		return nil, errors.New("the -config flag must be set")
+	}
+	if len(pass.Files) == 0 {
+		return nil, nil
+	}
+	files := make(map[string]*token.File, len(pass.Files))
+	for _, f := range pass.Files {
+		tf := pass.Fset.File(f.Pos())
+		files[tf.Name()] = tf
+	}
+	dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+	folder, ok := moduleFolder(dir)
+	if !ok {
+		return nil, nil
+	}
+	diags, err := c.checkModule(folder)
+	if err != nil {
+		return nil, err
+	}
+	// diagnostics without a position in a Go file are reported once, by the package of the module folder.
+	reportsModule := dir == folder && !strings.HasSuffix(pass.Pkg.Name(), "_test")
+	for _, d := range diags {
+		if tf, ok := files[d.Position.File]; ok && d.Position.Line > 0 && d.Position.Line <= tf.LineCount() {
+			pos := tf.LineStart(d.Position.Line)
+			if d.Position.Column > 0 {
+				pos += token.Pos(d.Position.Column - 1)
+			}
+			pass.Report(analysis.Diagnostic{Pos: pos, Category: d.Rule, Message: d.Message})
+		} else if reportsModule && filepath.Ext(d.Position.File) != ".go" {
+			message := d.Message
+			if rel, err := filepath.Rel(folder, d.Position.File); err == nil && d.Position.File != "" {
+				message = rel + ": " + message
+			}
+			pass.Report(analysis.Diagnostic{Pos: pass.Files[0].Package, Category: d.Rule, Message: message})
+		}
+	}
+	return nil, nil
+}
+
+// checkModule returns the diagnostics of the module in folder, if it has a metadata.yaml file.
+// The module is only checked again if its files or the configuration changed.
+func (c *checker) checkModule(folder string) ([]internal.Diagnostic, error) {
+	cfg, err := internal.ReadConfig(c.configPath)
+	if err != nil {
+		return nil, err
+	}
+	metadata, found, err := internal.ReadMetadata(folder)
+	if err != nil || !found {
+		return nil, err
+	}
+	moduleCfg, err := cfg.ForModule(folder, metadata)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := internal.ModuleHash(folder, moduleCfg)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	result, ok := c.modules[folder]
+	if !ok || result.hash != hash {
+		result = &moduleResult{hash: hash}
+		c.modules[folder] = result
+	}
+	c.mu.Unlock()
+	result.once.Do(func() {
+		result.diags, result.err = c.check(folder, moduleCfg, metadata)
+	})
+	return result.diags, result.err
+}
+
+func (c *checker) check(folder string, cfg internal.Config, metadata internal.Metadata) ([]internal.Diagnostic, error) {
+	var version string
+	if c.versionsPath != "" {
+		versions, err := internal.ReadVersions(c.versionsPath)
+		if err != nil {
+			return nil, err
+		}
+		modulePath, err := internal.ReadModulePath(folder)
+		if err != nil {
+			return nil, err
+		}
+		_, version, _ = versions.ModuleSetOf(modulePath)
+	}
+	var modulePaths []string
+	if cfg.CrossModuleInternalImports.Enabled {
+		var err error
+		if modulePaths, err = internal.RepositoryModulePaths(folder); err != nil {
+			return nil, err
+		}
+	}
+	var cache *internal.Cache
+	if c.cacheDir != "" {
+		cache = internal.NewCache(c.cacheDir)
+	}
+	// as with the command, packages are internal if their path relative to the working directory has an internal folder.
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, folder); err == nil {
+			folder = rel
+		}
+	}
+	api, platformSpecific, err := internal.ReadModule(cfg, folder, cache)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", folder, err)
+	}
+	diags, err := internal.CheckModule(cfg, folder, metadata, version, modulePaths, api, platformSpecific)
+	for i, d := range diags {
+		if d.Position.File != "" {
+			diags[i].Position.File, _ = filepath.Abs(d.Position.File)
+		}
+	}
+	return diags, err
+}
+
+// moduleFolder returns the folder of the module holding dir, the closest folder with a go.mod file.
+func moduleFolder(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, filepath.Join("testdata", "pkg"), New(filepath.Join("testdata", "config.yaml")), "./...")
+}
+
+func TestAnalyzerWithoutConfig(t *testing.T) {
+	_, err := Analyzer.Run(&analysis.Pass{})
+	require.EqualError(t, err, "the -config flag must be set")
+}
@@ -0,0 +1,3 @@
+module foo/bar/analyzerpkg/pkg
+
+go 1.25.0
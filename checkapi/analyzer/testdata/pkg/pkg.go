@@ -0,0 +1,15 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkg // want "no function matching configuration found"
+
+var DefaultName = "pkg" // want `variable "DefaultName" of type "string" must not be exported, as it can be modified by any importer`
+
+type Point struct { // want `struct "Point" does not prevent unkeyed literal initialization`
+	X int
+	Y int
+}
+
+func Distance(p Point) int { // want `function "Distance" should not be exported`
+	return p.X + p.Y
+}
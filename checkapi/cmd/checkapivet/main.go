@@ -0,0 +1,17 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Checkapivet runs the rules of checkapi as a go vet tool:
+//
+//	go vet -vettool=$(which checkapivet) -checkapi.config=$PWD/config.yaml ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"go.opentelemetry.io/build-tools/checkapi/analyzer"
+)
+
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
@@ -6,6 +6,8 @@ require (
 	github.com/goccy/go-json v0.10.6
 	github.com/kaptinlin/jsonschema v0.6.10
 	github.com/stretchr/testify v1.12.0
+	golang.org/x/mod v0.40.0
+	golang.org/x/tools v0.49.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -17,6 +19,7 @@ require (
 	github.com/kaptinlin/messageformat-go v0.4.10 // indirect
 	github.com/kr/pretty v0.3.1 // indirect
 	github.com/rogpeppe/go-internal v1.16.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
 	golang.org/x/text v0.41.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AffectedModules holds the modules affected by a set of changed files.
+type AffectedModules struct {
+	// modules are the folders, relative to the root folder, of the modules owning a changed file.
+	modules map[string]struct{}
+	// packages are the import paths of the packages with a changed Go file.
+	packages map[string]struct{}
+	// folder is the root folder.
+	folder string
+	// moduleFolders maps the paths of the modules under the root folder to their folder, relative to it.
+	moduleFolders map[string]string
+}
+
+// ChangedFiles returns the files changed since the merge base of baseRef and HEAD in the git repository
+// holding folder, including uncommitted changes and untracked files that are not ignored, relative to folder.
+func ChangedFiles(folder string, baseRef string) ([]string, error) {
+	mergeBase, err := git(folder, "merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	out, err := git(folder, "diff", "--name-only", "--relative", "-z", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(folder, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	return append(splitNUL(out), splitNUL(untracked)...), nil
+}
+
+// splitNUL splits the NUL-separated paths written by git with -z, which are neither quoted nor escaped.
+func splitNUL(out string) []string {
+	var paths []string
+	for path := range strings.SplitSeq(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func git(folder string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = folder
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// ReadChangedFiles reads a list of changed files, one per line, as written by git diff --name-only.
+func ReadChangedFiles(path string) ([]string, error) {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var files []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, scanner.Err()
+}
+
+// FindAffectedModules maps the changed files, relative to folder, to the modules owning them:
+// like get_affected_pkgs.sh, every module whose folder holds a changed file, directly or in a subfolder, is affected.
+// The changed Go files also mark their package as changed.
+func FindAffectedModules(folder string, files []string) (AffectedModules, error) {
+	moduleFolders, err := ModuleFolders(folder)
+	if err != nil {
+		return AffectedModules{}, err
+	}
+	affected := AffectedModules{modules: map[string]struct{}{}, packages: map[string]struct{}{}, folder: folder, moduleFolders: moduleFolders}
+	for _, file := range files {
+		file = filepath.Clean(filepath.FromSlash(file))
+		owner := ""
+		for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+			_, err := os.Stat(filepath.Join(folder, dir, "go.mod"))
+			if err == nil {
+				affected.modules[dir] = struct{}{}
+				if owner == "" {
+					owner = dir
+				}
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return AffectedModules{}, err
+			}
+			if dir == "." || dir == string(filepath.Separator) {
+				break
+			}
+		}
+		if owner == "" || filepath.Ext(file) != ".go" {
+			continue
+		}
+		modulePath, err := ReadModulePath(filepath.Join(folder, owner))
+		if err != nil {
+			return AffectedModules{}, err
+		}
+		rel, err := filepath.Rel(owner, filepath.Dir(file))
+		if err != nil {
+			return AffectedModules{}, err
+		}
+		affected.packages[path.Join(modulePath, filepath.ToSlash(rel))] = struct{}{}
+	}
+	return affected, nil
+}
+
+// Includes reports whether the module in the base folder, at relativeBase under the root folder, must be checked:
+// it owns a changed file, or the files of its root package, which declare its config struct, import a changed package,
+// directly or through the packages of the modules under the root folder.
+func (a AffectedModules) Includes(base string, relativeBase string) (bool, error) {
+	if _, ok := a.modules[relativeBase]; ok {
+		return true, nil
+	}
+	if len(a.packages) == 0 {
+		return false, nil
+	}
+	modulePaths := slices.Collect(maps.Keys(a.moduleFolders))
+	visited := map[string]bool{base: true}
+	queue := []string{base}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		// the test files of the root package are read too, as they may declare its config struct.
+		imports, err := packageImports(dir, dir == base)
+		if err != nil {
+			return false, err
+		}
+		for _, importPath := range imports {
+			if _, ok := a.packages[importPath]; ok {
+				return true, nil
+			}
+			module := owningModule(importPath, modulePaths)
+			if module == "" {
+				continue
+			}
+			rel := strings.TrimPrefix(strings.TrimPrefix(importPath, module), "/")
+			importDir := filepath.Join(a.folder, a.moduleFolders[module], filepath.FromSlash(rel))
+			if !visited[importDir] {
+				visited[importDir] = true
+				queue = append(queue, importDir)
+			}
+		}
+	}
+	return false, nil
+}
+
+// packageImports returns the import paths of the Go files in dir, including test files if tests is set.
+func packageImports(dir string, tests bool) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var imports []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if !tests && strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range f.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			imports = append(imports, importPath)
+		}
+	}
+	return imports, nil
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestFindAffectedModules(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shared/go.mod":       "module example.com/shared\n",
+		"shared/sub/sub.go":   "package sub\n\nimport \"example.com/shared/deep\"\n\nvar _ deep.T\n",
+		"shared/deep/deep.go": "package deep\n",
+		"user/go.mod":         "module example.com/user\n",
+		"user/config.go":      "package user\n\nimport \"example.com/shared/sub\"\n\nvar _ sub.T\n",
+		"other/go.mod":        "module example.com/other\n",
+		"other/config.go":     "package other\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n",
+		"other/nested/go.mod": "module example.com/other/nested\n",
+	})
+
+	for _, tt := range []struct {
+		name     string
+		files    []string
+		expected []string
+	}{
+		{
+			name:     "changed package imported by another module",
+			files:    []string{"shared/sub/sub.go"},
+			expected: []string{"shared", "user"},
+		},
+		{
+			name:     "changed package imported transitively by another module",
+			files:    []string{"shared/deep/deep.go"},
+			expected: []string{"shared", "user"},
+		},
+		{
+			name:     "changed file of a nested module",
+			files:    []string{"other/nested/README.md"},
+			expected: []string{"other", "other/nested"},
+		},
+		{
+			name:  "files outside of any module",
+			files: []string{"README.md", "deleted/file.go"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			affected, err := FindAffectedModules(dir, tt.files)
+			require.NoError(t, err)
+			var modules []string
+			for _, m := range []string{"other", "other/nested", "shared", "user"} {
+				ok, err := affected.Includes(filepath.Join(dir, filepath.FromSlash(m)), filepath.FromSlash(m))
+				require.NoError(t, err)
+				if ok {
+					modules = append(modules, m)
+				}
+			}
+			assert.Equal(t, tt.expected, modules)
+		})
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("init", "-q", "-b", "main")
+	writeFiles(t, dir, map[string]string{"a/a.go": "package a\n", "b/b.go": "package b\n", "c/c.go": "package c\n"})
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+	run("checkout", "-q", "-b", "feature")
+	// paths with spaces or non-ASCII characters are quoted by git unless -z is set.
+	writeFiles(t, dir, map[string]string{"a/a.go": "package a\n\nconst A = 1\n", "a/my file.go": "package a\n"})
+	run("add", "-A")
+	run("commit", "-q", "-m", "change a")
+	writeFiles(t, dir, map[string]string{"b/b.go": "package b\n\nconst B = 1\n", "d/d.go": "package d\n", "d/é.go": "package d\n", ".gitignore": "*.log\n", "d/debug.log": "log\n"})
+
+	files, err := ChangedFiles(dir, "main")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/a.go", "a/my file.go", "b/b.go", ".gitignore", "d/d.go", "d/é.go"}, files)
+
+	_, err = ChangedFiles(dir, "unknown")
+	require.ErrorContains(t, err, "git merge-base unknown HEAD")
+}
+
+func TestReadChangedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changed.txt")
+	require.NoError(t, os.WriteFile(path, []byte("a/a.go\n\n  b/b.go\n"), 0o600))
+	files, err := ReadChangedFiles(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/a.go", "b/b.go"}, files)
+}
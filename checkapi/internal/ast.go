@@ -7,16 +7,19 @@ package internal
 import (
 	"fmt"
 	"go/ast"
-	"go/parser"
+	"go/constant"
 	"go/token"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"go/types"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 // ExprToString converts an AST expression to a string representation.
+// It is used for expressions that the type checker could not resolve.
 func ExprToString(expr ast.Expr) string {
 	switch e := expr.(type) {
 	case *ast.MapType:
@@ -91,92 +94,83 @@ func ExprToString(expr ast.Expr) string {
 	case *ast.ParenExpr:
 		return fmt.Sprintf("(%s)", ExprToString(e.X))
 	default:
-		panic(fmt.Sprintf("Unsupported expr type: %#v", expr))
+		return types.ExprString(expr)
 	}
 }
 
-// Read reads the Go files in the specified folder and returns an API object.
-func Read(folder string, ignoredFunctions []string, excludedFiles []string) (API, error) {
-	result := &API{}
-	set := token.NewFileSet()
-
-	readErr := filepath.Walk(folder, func(path string, info fs.FileInfo, _ error) error {
-		if info.IsDir() {
+// componentConfigType is the canonical return type of the function creating the default config of a component.
+const componentConfigType = "go.opentelemetry.io/collector/component.Config"
 
-			isInternal := false
-			for _, s := range strings.Split(path, string(os.PathSeparator)) {
-				if s == "internal" {
-					isInternal = true
-				}
-			}
-			goModPresent := false
-			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
-				goModPresent = true
-			}
-			// if a subfolder has its own go.mod, do not read the folder.
-			if path != folder && goModPresent {
-				return nil
-			}
-
-			packs, err := parseDir(set, path)
-			if err != nil {
-				return err
-			}
+// packageReader reads the API declared in the files of a package.
+type packageReader struct {
+	// info holds the results of type checking the package. Types missing from it are read from the syntax.
+	info             *types.Info
+	qualifier        types.Qualifier
+	ignoredFunctions []string
+	internal         bool
+	// packageName is the qualifier of the types of the package, its import path relative to the module,
+	// empty for the root package of the module.
+	packageName string
+	funcDecls   map[*types.Func]*ast.FuncDecl
+	position    func(token.Pos) Position
+	result      *API
+}
 
-			for _, pack := range packs {
-				if err := readPackage(pack, ignoredFunctions, excludedFiles, result, isInternal, path == folder); err != nil {
-					return err
+func newPackageReader(info *types.Info, qualifier types.Qualifier, position func(token.Pos) Position, files []*ast.File, ignoredFunctions []string, internal bool, packageName string, result *API) *packageReader {
+	r := &packageReader{
+		info:             info,
+		qualifier:        qualifier,
+		position:         position,
+		ignoredFunctions: ignoredFunctions,
+		internal:         internal,
+		packageName:      packageName,
+		funcDecls:        map[*types.Func]*ast.FuncDecl{},
+		result:           result,
+	}
+	if info != nil {
+		for _, f := range files {
+			for _, d := range f.Decls {
+				if fn, ok := d.(*ast.FuncDecl); ok {
+					if obj, ok := info.Defs[fn.Name].(*types.Func); ok {
+						r.funcDecls[obj] = fn
+					}
 				}
 			}
 		}
-		return nil
-	})
-
-	return *result, readErr
+	}
+	return r
 }
 
-// simplified version of deprecated https://cs.opensource.google/go/go/+/refs/tags/go1.26.0:src/go/parser/interface.go;l=153
-func parseDir(fset *token.FileSet, dir string) (map[string]*ast.Package, error) { // nolint:staticcheck // SA1019
-	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
-	if err != nil {
-		return nil, err
+// typeString returns the canonical representation of the type of an expression,
+// or its syntax if the type checker could not resolve it.
+func (r *packageReader) typeString(expr ast.Expr) string {
+	if e, ok := expr.(*ast.Ellipsis); ok {
+		return r.typeString(e.Elt) + "..."
 	}
-	pkgs := map[string]*ast.Package{} // nolint:staticcheck // SA1019
-	for _, f := range files {
-		file, err := parser.ParseFile(fset, f, nil, 0)
-		if err != nil {
-			return nil, err
-		}
-		if pkgs[file.Name.Name] == nil {
-			pkgs[file.Name.Name] = &ast.Package{Name: file.Name.Name, Files: map[string]*ast.File{}} // nolint:staticcheck // SA1019
+	if r.info != nil {
+		if tv, ok := r.info.Types[expr]; ok && isValidType(tv.Type) {
+			return TypeString(tv.Type, r.qualifier)
 		}
-		pkgs[file.Name.Name].Files[f] = file
 	}
-	return pkgs, nil
+	return ExprToString(expr)
 }
 
-func readPackage(pack *ast.Package, ignoredFunctions []string, excludedFiles []string, result *API, internal bool, root bool) error { // nolint:staticcheck // SA1019
-FILE:
-	for path, f := range pack.Files {
-		for _, exclusionPattern := range excludedFiles {
-			ok, err2 := filepath.Match(exclusionPattern, filepath.Base(path))
-			if err2 != nil {
-				return err2
-			}
-			if ok {
-				continue FILE
-			}
-		}
-		packageName := pack.Name
-		if root {
-			packageName = ""
+// fieldListTypes returns the type of each entry of a field list, repeated for each name sharing the type.
+func (r *packageReader) fieldListTypes(fields *ast.FieldList) []string {
+	if fields.NumFields() == 0 {
+		return nil
+	}
+	var result []string
+	for _, f := range fields.List {
+		t := r.typeString(f.Type)
+		for range max(1, len(f.Names)) {
+			result = append(result, t)
 		}
-		readFile(ignoredFunctions, f, result, internal, packageName)
 	}
-	return nil
+	return result
 }
 
-func interpretFieldType(f *ast.Field, expr ast.Expr) []APIstructField {
+func (r *packageReader) interpretFieldType(f *ast.Field, expr ast.Expr) []APIstructField {
 	var fieldNames []APIstructField
 	fieldType := expr
 	switch t := fieldType.(type) {
@@ -192,7 +186,7 @@ func interpretFieldType(f *ast.Field, expr ast.Expr) []APIstructField {
 		if tt, ok := fieldType.(*ast.StarExpr); ok {
 			fieldType = tt.X
 		}
-		fieldNames = interpretFieldType(f, t.Key)
+		fieldNames = r.interpretFieldType(f, t.Key)
 	case *ast.Ident:
 		// nothing to do
 	case *ast.ChanType:
@@ -201,24 +195,44 @@ func interpretFieldType(f *ast.Field, expr ast.Expr) []APIstructField {
 		// nothing to do
 	case *ast.IndexExpr:
 		fieldType = t.X
-		fieldNames = interpretFieldType(f, t.Index)
+		fieldNames = r.interpretFieldType(f, t.Index)
+	case *ast.IndexListExpr:
+		fieldType = t.X
+		for _, index := range t.Indices {
+			fieldNames = append(fieldNames, r.interpretFieldType(f, index)...)
+		}
+	}
+	switch fieldType.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// generic element types, as in []Foo[T], are split like generic fields.
+		if fieldType != expr {
+			return append(fieldNames, r.interpretFieldType(f, fieldType)...)
+		}
 	}
 	tag := ""
 	if f.Tag != nil {
 		tag = f.Tag.Value
 	}
-	fieldNames = append(fieldNames, APIstructField{Name: f.Names[0].Name, Type: ExprToString(fieldType), Tag: tag})
+	fieldNames = append(fieldNames, APIstructField{Name: f.Names[0].Name, Type: r.typeString(fieldType), Tag: tag})
 	return fieldNames
 }
 
-func readFile(ignoredFunctions []string, f *ast.File, result *API, internal bool, packageName string) {
+func (r *packageReader) readFile(f *ast.File) {
+	for _, imp := range f.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil {
+			r.result.Imports = append(r.result.Imports, APIimport{Path: path, Pos: r.position(imp.Path.Pos())})
+		}
+	}
 	for _, d := range f.Decls {
 		if str, isStr := d.(*ast.GenDecl); isStr {
 			for _, s := range str.Specs {
 				if values, ok := s.(*ast.ValueSpec); ok {
 					for _, v := range values.Names {
 						if v.IsExported() {
-							result.Values = append(result.Values, v.Name)
+							r.result.Values = append(r.result.Values, r.readValue(str.Tok, values, v))
+						}
+						if str.Tok == token.CONST {
+							r.readEnumValue(v)
 						}
 					}
 				}
@@ -229,7 +243,15 @@ func readFile(ignoredFunctions []string, f *ast.File, result *API, internal bool
 							fieldNames = make([]APIstructField, 0, len(structType.Fields.List))
 							for _, f := range structType.Fields.List {
 								if len(f.Names) > 0 {
-									fieldNames = append(fieldNames, interpretFieldType(f, f.Names[0].Obj.Decl.(*ast.Field).Type)...)
+									fields := r.interpretFieldType(f, f.Type)
+									fullType := r.typeString(f.Type)
+									for i := range fields {
+										if fields[i].Type != fullType {
+											fields[i].FullType = fullType
+										}
+										fields[i].Doc = fieldDoc(f)
+									}
+									fieldNames = append(fieldNames, fields...)
 								} else {
 									// Embedded struct
 									fieldType := f.Type
@@ -246,27 +268,51 @@ func readFile(ignoredFunctions []string, f *ast.File, result *API, internal bool
 									if f.Tag != nil {
 										tag = f.Tag.Value
 									}
-									fieldNames = append(fieldNames, APIstructField{Name: "", Type: ExprToString(fieldType), Tag: tag})
+									field := APIstructField{Name: "", Type: r.typeString(fieldType), Tag: tag, Doc: fieldDoc(f)}
+									if fullType := r.typeString(f.Type); fullType != field.Type {
+										field.FullType = fullType
+									}
+									fieldNames = append(fieldNames, field)
 								}
 							}
 						}
-						name := t.Name.String()
-						if packageName != "" {
-							name = fmt.Sprintf("%s.%s", packageName, t.Name)
+						var typeParams []string
+						if t.TypeParams != nil {
+							for _, p := range t.TypeParams.List {
+								for _, n := range p.Names {
+									typeParams = append(typeParams, n.Name)
+								}
+							}
 						}
-						result.Structs = append(result.Structs, APIstruct{
-							Name:     name,
-							Fields:   fieldNames,
-							Internal: internal,
+						name := qualifiedName(r.packageName, t.Name.Name)
+						r.result.Structs = append(r.result.Structs, APIstruct{
+							Name:       name,
+							TypeParams: typeParams,
+							Fields:     fieldNames,
+							Internal:   r.internal,
+							Pos:        r.position(t.Name.Pos()),
+						})
+					} else if interfaceType, ok := t.Type.(*ast.InterfaceType); ok && !t.Assign.IsValid() {
+						r.result.Interfaces = append(r.result.Interfaces, r.readInterface(t, interfaceType))
+					} else {
+						r.result.Types = append(r.result.Types, APItype{
+							Name:     qualifiedName(r.packageName, t.Name.Name),
+							Type:     r.typeString(t.Type),
+							Alias:    t.Assign.IsValid(),
+							Internal: r.internal,
+							Pos:      r.position(t.Name.Pos()),
 						})
 					}
 				}
 			}
 		}
 		if fn, isFn := d.(*ast.FuncDecl); isFn {
+			if fn.Recv.NumFields() > 0 {
+				r.readMethod(fn)
+			}
 			exported := false
 			receiver := ""
-			if fn.Recv.NumFields() == 0 && !isFunctionIgnored(ignoredFunctions, fn.Name.String()) {
+			if fn.Recv.NumFields() == 0 && !isFunctionIgnored(r.ignoredFunctions, fn.Name.String()) {
 				exported = true
 			}
 			if fn.Recv.NumFields() > 0 {
@@ -280,18 +326,6 @@ func readFile(ignoredFunctions []string, f *ast.File, result *API, internal bool
 				}
 			}
 			if exported {
-				var returnTypes []string
-				if fn.Type.Results.NumFields() > 0 {
-					for _, r := range fn.Type.Results.List {
-						returnTypes = append(returnTypes, ExprToString(r.Type))
-					}
-				}
-				var params []string
-				if fn.Type.Params.NumFields() > 0 {
-					for _, r := range fn.Type.Params.List {
-						params = append(params, ExprToString(r.Type))
-					}
-				}
 				var typeParams []string
 				if fn.Type.TypeParams.NumFields() > 0 {
 					for _, r := range fn.Type.TypeParams.List {
@@ -300,64 +334,332 @@ func readFile(ignoredFunctions []string, f *ast.File, result *API, internal bool
 				}
 
 				apiFn := Function{
-					Name:        fn.Name.Name,
-					Receiver:    receiver,
-					Params:      params,
-					ReturnTypes: returnTypes,
-					TypeParams:  typeParams,
-					Internal:    internal,
+					Name:           fn.Name.Name,
+					Receiver:       receiver,
+					Params:         r.fieldListTypes(fn.Type.Params),
+					ReturnTypes:    r.fieldListTypes(fn.Type.Results),
+					TypeParams:     typeParams,
+					TypeParamNames: typeParamNames(fn),
+					Internal:       r.internal,
+					Pos:            r.position(fn.Name.Pos()),
 				}
-				if !fn.Name.IsExported() && len(apiFn.ReturnTypes) == 1 && apiFn.ReturnTypes[0] == "component.Config" {
-					result.ConfigStructName = extractFunctionReturnType(fn)
+				if !fn.Name.IsExported() && len(apiFn.ReturnTypes) == 1 && apiFn.ReturnTypes[0] == componentConfigType {
+					r.result.ConfigStructName = r.extractFunctionReturnType(fn)
+					r.result.ConfigDefaults = r.evaluateConfig(fn)
 				} else if fn.Name.IsExported() {
-					result.Functions = append(result.Functions, apiFn)
+					r.result.Functions = append(r.result.Functions, apiFn)
 				}
 
 			}
 		}
 	}
+	r.readDeprecations(f)
+}
+
+// readValue returns a package-level constant or variable, typed by the type checker
+// or, if it could not resolve it, by its declared type.
+func (r *packageReader) readValue(tok token.Token, spec *ast.ValueSpec, name *ast.Ident) APIvalue {
+	kind := ValueKindVar
+	if tok == token.CONST {
+		kind = ValueKindConst
+	}
+	valueType := ""
+	if r.info != nil && r.info.Defs[name] != nil {
+		valueType = TypeString(r.info.Defs[name].Type(), r.qualifier)
+	} else if spec.Type != nil {
+		valueType = ExprToString(spec.Type)
+	}
+	return APIvalue{
+		Name:     qualifiedName(r.packageName, name.Name),
+		Kind:     kind,
+		Type:     valueType,
+		Internal: r.internal,
+		Pos:      r.position(name.Pos()),
+	}
+}
+
+// readMethod records the signature of a method under the name of its receiver type.
+func (r *packageReader) readMethod(fn *ast.FuncDecl) {
+	recv := receiverTypeName(fn.Recv.List[0].Type)
+	if recv == "" {
+		return
+	}
+	if r.result.Methods == nil {
+		r.result.Methods = map[string][]Function{}
+	}
+	name := qualifiedName(r.packageName, recv)
+	r.result.Methods[name] = append(r.result.Methods[name], Function{
+		Name:        fn.Name.Name,
+		Receiver:    name,
+		Params:      r.fieldListTypes(fn.Type.Params),
+		ReturnTypes: r.fieldListTypes(fn.Type.Results),
+		Internal:    r.internal,
+		Pos:         r.position(fn.Name.Pos()),
+	})
+}
+
+// readDeprecations records the exported declarations of a file documented as deprecated:
+// functions, methods, types, struct fields, constants and variables.
+func (r *packageReader) readDeprecations(f *ast.File) {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv.NumFields() > 0 {
+				recv := receiverTypeName(d.Recv.List[0].Type)
+				if !ast.IsExported(recv) {
+					continue
+				}
+				name = recv + "." + name
+			}
+			r.recordDeprecation(d.Name, name, d.Doc)
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				doc := specDoc(d, s)
+				switch s := s.(type) {
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						r.recordDeprecation(n, n.Name, doc)
+					}
+				case *ast.TypeSpec:
+					r.recordDeprecation(s.Name, s.Name.Name, doc)
+					if structType, ok := s.Type.(*ast.StructType); ok && s.Name.IsExported() {
+						for _, field := range structType.Fields.List {
+							for _, n := range field.Names {
+								r.recordDeprecation(n, s.Name.Name+"."+n.Name, field.Doc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// recordDeprecation records an exported declaration if its doc comment has a paragraph starting with "Deprecated:".
+func (r *packageReader) recordDeprecation(ident *ast.Ident, name string, doc *ast.CommentGroup) {
+	if !ident.IsExported() {
+		return
+	}
+	notice := deprecationNotice(doc.Text())
+	if notice == "" {
+		return
+	}
+	r.result.Deprecations = append(r.result.Deprecations, Deprecation{
+		Name:     qualifiedName(r.packageName, name),
+		Notice:   notice,
+		Internal: r.internal,
+		Pos:      r.position(ident.Pos()),
+	})
+}
+
+// deprecationNotice returns the paragraph of a doc comment starting with "Deprecated:", joined on a single line.
+func deprecationNotice(doc string) string {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated:") {
+			return strings.Join(strings.Fields(paragraph), " ")
+		}
+	}
+	return ""
+}
+
+// specDoc returns the doc comment of a spec, or the one of its declaration if it declares a single spec without parentheses.
+func specDoc(d *ast.GenDecl, s ast.Spec) *ast.CommentGroup {
+	var doc *ast.CommentGroup
+	switch s := s.(type) {
+	case *ast.ValueSpec:
+		doc = s.Doc
+	case *ast.TypeSpec:
+		doc = s.Doc
+	}
+	if doc == nil && !d.Lparen.IsValid() {
+		doc = d.Doc
+	}
+	return doc
+}
+
+// receiverTypeName returns the name of the type of a method receiver.
+func receiverTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(t.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// typeParamNames returns the names of the type parameters of a function, and of the type of its receiver.
+func typeParamNames(fn *ast.FuncDecl) []string {
+	var names []string
+	if fn.Recv.NumFields() > 0 {
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		var indices []ast.Expr
+		switch t := recv.(type) {
+		case *ast.IndexExpr:
+			indices = []ast.Expr{t.Index}
+		case *ast.IndexListExpr:
+			indices = t.Indices
+		}
+		for _, index := range indices {
+			if ident, ok := index.(*ast.Ident); ok {
+				names = append(names, ident.Name)
+			}
+		}
+	}
+	if fn.Type.TypeParams != nil {
+		for _, p := range fn.Type.TypeParams.List {
+			for _, n := range p.Names {
+				names = append(names, n.Name)
+			}
+		}
+	}
+	return names
+}
+
+// readEnumValue records the value of a constant declared with a named string type.
+func (r *packageReader) readEnumValue(name *ast.Ident) {
+	if r.info == nil {
+		return
+	}
+	c, ok := r.info.Defs[name].(*types.Const)
+	if !ok || c.Val().Kind() != constant.String {
+		return
+	}
+	named, ok := types.Unalias(c.Type()).(*types.Named)
+	if !ok || !isValidType(named) {
+		return
+	}
+	if r.result.Enums == nil {
+		r.result.Enums = map[string][]string{}
+	}
+	typeName := TypeString(named, r.qualifier)
+	value := constant.StringVal(c.Val())
+	if !slices.Contains(r.result.Enums[typeName], value) {
+		r.result.Enums[typeName] = append(r.result.Enums[typeName], value)
+	}
+}
+
+// fieldDoc returns the doc comment of a struct field, or its line comment if it has none.
+func fieldDoc(f *ast.Field) string {
+	if doc := strings.TrimSpace(f.Doc.Text()); doc != "" {
+		return doc
+	}
+	return strings.TrimSpace(f.Comment.Text())
+}
+
+// qualifiedName prefixes the name of a type with the name of its package, unless it is declared in the root package.
+func qualifiedName(packageName string, name string) string {
+	if packageName == "" {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", packageName, name)
+}
+
+// readInterface reads the method set of an interface, including the methods promoted from embedded interfaces
+// when the type checker resolved them, and the embedded types as they are declared.
+func (r *packageReader) readInterface(t *ast.TypeSpec, interfaceType *ast.InterfaceType) APIinterface {
+	name := qualifiedName(r.packageName, t.Name.Name)
+	result := APIinterface{Name: name, Internal: r.internal, Pos: r.position(t.Name.Pos())}
+	for _, m := range interfaceType.Methods.List {
+		if len(m.Names) == 0 {
+			// embedded interface or type constraint
+			result.Embedded = append(result.Embedded, r.typeString(m.Type))
+		}
+	}
+
+	if r.info != nil {
+		if obj, ok := r.info.Defs[t.Name].(*types.TypeName); ok && isValidType(obj.Type()) {
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+				for i := range iface.NumMethods() {
+					m := iface.Method(i)
+					params, results := signatureStrings(m.Type().(*types.Signature), r.qualifier)
+					result.Methods = append(result.Methods, Function{
+						Name:        m.Name(),
+						Receiver:    name,
+						Params:      params,
+						ReturnTypes: results,
+						Internal:    r.internal,
+					})
+				}
+				return result
+			}
+		}
+	}
+
+	for _, m := range interfaceType.Methods.List {
+		fnType, ok := m.Type.(*ast.FuncType)
+		if !ok || len(m.Names) == 0 {
+			continue
+		}
+		for _, n := range m.Names {
+			result.Methods = append(result.Methods, Function{
+				Name:        n.Name,
+				Receiver:    name,
+				Params:      r.fieldListTypes(fnType.Params),
+				ReturnTypes: r.fieldListTypes(fnType.Results),
+				Internal:    r.internal,
+			})
+		}
+	}
+	return result
 }
 
-func extractFunctionReturnType(fn *ast.FuncDecl) string {
+// extractFunctionReturnType returns the name of the struct returned by a function, following the functions it delegates to.
+func (r *packageReader) extractFunctionReturnType(fn *ast.FuncDecl) string {
+	if fn.Body == nil || len(fn.Body.List) == 0 {
+		return ""
+	}
 	ret, ok := fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
 	if !ok || len(ret.Results) != 1 {
 		return ""
 	}
-	switch r := ret.Results[0].(type) {
+	result := ret.Results[0]
+	if r.info != nil {
+		if t := r.info.TypeOf(result); isValidType(t) {
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if named, ok := types.Unalias(t).(*types.Named); ok {
+				if _, ok := named.Underlying().(*types.Struct); ok {
+					return TypeString(named.Origin(), r.qualifier)
+				}
+			}
+		}
+		if call, ok := result.(*ast.CallExpr); ok {
+			if callee, ok := typeutil.Callee(r.info, call).(*types.Func); ok {
+				if decl, ok := r.funcDecls[callee]; ok && decl != fn {
+					return r.extractFunctionReturnType(decl)
+				}
+			}
+		}
+	}
+
+	// fall back to the syntax when the type checker could not resolve the returned value.
+	switch x := result.(type) {
 	case *ast.UnaryExpr:
-		switch x := r.X.(type) {
+		switch x := x.X.(type) {
 		case *ast.Ident:
 			return x.Name
 		case *ast.CompositeLit:
-			switch subt := x.Type.(type) {
-			case *ast.Ident:
-				return subt.Name
-			case *ast.SelectorExpr:
-				return subt.X.(*ast.Ident).Name
-			}
+			return ExprToString(x.Type)
 		}
+	case *ast.CompositeLit:
+		return ExprToString(x.Type)
 	case *ast.Ident:
 		// The identifier may name a variable holding the config rather than the config
 		// type, as in `cfg := &Config{...}; return cfg`.
-		if name := structNameFromIdent(r); name != "" {
+		if name := structNameFromIdent(x); name != "" {
 			return name
 		}
-		return r.Name
-	case *ast.CallExpr:
-		switch x := r.Fun.(type) {
-		case *ast.SelectorExpr:
-			if x.Sel.Obj != nil {
-				return extractFunctionReturnType(x.Sel.Obj.Decl.(*ast.FuncDecl))
-			}
-		case *ast.Ident:
-			if x.Obj != nil {
-				return extractFunctionReturnType(x.Obj.Decl.(*ast.FuncDecl))
-			}
-		default:
-			panic(fmt.Sprintf("[%s] Unsupported function reference %T", fn.Name.Name, x))
-		}
-	default:
-		panic(fmt.Sprintf("[%s] Unsupported type %T", fn.Name.Name, r))
+		return x.Name
 	}
 	return ""
 }
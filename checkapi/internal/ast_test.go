@@ -151,6 +151,14 @@ func TestAST(t *testing.T) {
 			},
 			"func[T ~string](T)",
 		},
+		{
+			"unsupported by the syntax reader",
+			&ast.SliceExpr{
+				X:   ast.NewIdent("foo"),
+				Low: &ast.BasicLit{Value: "1"},
+			},
+			"foo[1:]",
+		},
 	}
 
 	for _, test := range tests {
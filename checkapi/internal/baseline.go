@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// baselineHeader is written at the top of baseline files.
+const baselineHeader = "# Known violations of checkapi rules, which do not fail the check.\n# Generated with -write-baseline, shrink it with -prune-baseline as violations get fixed.\n"
+
+// BaselineEntry is a known violation of a rule by a module.
+type BaselineEntry struct {
+	Rule string `yaml:"rule"`
+	// Module is the folder of the module, relative to the folder investigated for modules.
+	Module  string `yaml:"module"`
+	Message string `yaml:"message"`
+}
+
+// Baseline lists the known violations that do not fail the check. Violations are identified by their rule,
+// module and message, regardless of their position, so that unrelated changes of their files do not invalidate them.
+type Baseline struct {
+	Violations []BaselineEntry `yaml:"violations"`
+}
+
+// NewBaseline returns a baseline recording the diagnostics with an error severity of the modules under folder.
+func NewBaseline(folder string, diags []Diagnostic) Baseline {
+	var b Baseline
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			b.Violations = append(b.Violations, baselineEntry(folder, d))
+		}
+	}
+	b.sort()
+	return b
+}
+
+// ReadBaseline reads a baseline file.
+func ReadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return Baseline{}, err
+	}
+	var b Baseline
+	if err = yaml.Unmarshal(data, &b); err != nil {
+		return Baseline{}, fmt.Errorf("cannot read baseline %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// WriteBaseline writes a baseline file, sorted so that it always serializes the same way.
+func WriteBaseline(path string, b Baseline) error {
+	b.sort()
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(baselineHeader), data...), 0o600)
+}
+
+// Filter returns the diagnostics of the modules under folder that are not known violations,
+// and the known violations that no longer occur. A violation recorded once only matches one diagnostic.
+func (b Baseline) Filter(folder string, diags []Diagnostic) ([]Diagnostic, Baseline) {
+	known := map[BaselineEntry]int{}
+	for _, e := range b.Violations {
+		known[e]++
+	}
+	var remaining []Diagnostic
+	for _, d := range diags {
+		e := baselineEntry(folder, d)
+		if d.Severity == SeverityError && known[e] > 0 {
+			known[e]--
+			continue
+		}
+		remaining = append(remaining, d)
+	}
+	var stale Baseline
+	for _, e := range b.Violations {
+		if known[e] > 0 {
+			known[e]--
+			stale.Violations = append(stale.Violations, e)
+		}
+	}
+	return remaining, stale
+}
+
+// Without returns the baseline without the given violations.
+func (b Baseline) Without(removed Baseline) Baseline {
+	count := map[BaselineEntry]int{}
+	for _, e := range removed.Violations {
+		count[e]++
+	}
+	var result Baseline
+	for _, e := range b.Violations {
+		if count[e] > 0 {
+			count[e]--
+			continue
+		}
+		result.Violations = append(result.Violations, e)
+	}
+	return result
+}
+
+func (b Baseline) sort() {
+	slices.SortStableFunc(b.Violations, func(a, b BaselineEntry) int {
+		return strings.Compare(a.Module+"\x00"+a.Rule+"\x00"+a.Message, b.Module+"\x00"+b.Rule+"\x00"+b.Message)
+	})
+}
+
+// baselineEntry returns the baseline entry matching a diagnostic, with a module relative to folder.
+func baselineEntry(folder string, d Diagnostic) BaselineEntry {
+	module := d.Module
+	if rel, err := filepath.Rel(folder, d.Module); err == nil {
+		module = rel
+	}
+	return BaselineEntry{Rule: d.Rule, Module: filepath.ToSlash(module), Message: d.Message}
+}
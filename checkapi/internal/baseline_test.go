@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	folder := filepath.Join("repo")
+	diags := []Diagnostic{
+		{Rule: RuleComponentAPI, Severity: SeverityError, Module: filepath.Join("repo", "receiver", "foo"), Position: Position{Line: 3}, Message: "struct A"},
+		{Rule: RuleComponentAPI, Severity: SeverityError, Module: filepath.Join("repo", "receiver", "foo"), Message: "struct A"},
+		{Rule: RuleBreakingChanges, Severity: SeverityWarning, Module: filepath.Join("repo", "exporter", "bar"), Message: "removed"},
+		{Rule: RuleAllowedFunctions, Severity: SeverityError, Module: filepath.Join("repo", "exporter", "bar"), Message: "functions"},
+	}
+	baseline := NewBaseline(folder, diags)
+	assert.Equal(t, Baseline{Violations: []BaselineEntry{
+		{Rule: RuleAllowedFunctions, Module: "exporter/bar", Message: "functions"},
+		{Rule: RuleComponentAPI, Module: "receiver/foo", Message: "struct A"},
+		{Rule: RuleComponentAPI, Module: "receiver/foo", Message: "struct A"},
+	}}, baseline)
+
+	path := filepath.Join(t.TempDir(), "baseline", "checkapi.yaml")
+	require.NoError(t, WriteBaseline(path, baseline))
+	read, err := ReadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, baseline, read)
+
+	remaining, stale := baseline.Filter(folder, append(diags[1:], Diagnostic{Rule: RuleJSONSchema, Severity: SeverityError, Module: filepath.Join("repo", "receiver", "foo"), Message: "new"}))
+	assert.Equal(t, []Diagnostic{
+		diags[2],
+		{Rule: RuleJSONSchema, Severity: SeverityError, Module: filepath.Join("repo", "receiver", "foo"), Message: "new"},
+	}, remaining)
+	assert.Equal(t, Baseline{Violations: []BaselineEntry{{Rule: RuleComponentAPI, Module: "receiver/foo", Message: "struct A"}}}, stale)
+	assert.Equal(t, Baseline{Violations: []BaselineEntry{
+		{Rule: RuleAllowedFunctions, Module: "exporter/bar", Message: "functions"},
+		{Rule: RuleComponentAPI, Module: "receiver/foo", Message: "struct A"},
+	}}, baseline.Without(stale))
+
+	require.NoError(t, os.WriteFile(path, []byte("violations: {"), 0o600))
+	_, err = ReadBaseline(path)
+	require.ErrorContains(t, err, "cannot read baseline")
+}
@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/mod/modfile"
+)
+
+// cacheVersion changes when the format of the cached APIs changes, invalidating the existing entries.
+const cacheVersion = "4"
+
+// Cache stores the API read for modules on disk, keyed by a hash of their Go files and of the configuration.
+type Cache struct {
+	dir string
+}
+
+// CachedAPI is the API of a module stored in the cache.
+type CachedAPI struct {
+	API              API                           `json:"api"`
+	PlatformSpecific []PlatformSpecificDeclaration `json:"platform_specific,omitempty"`
+}
+
+// NewCache returns a cache storing its entries in dir.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Key returns the key of the module in folder, the hash returned by ModuleHash.
+func (*Cache) Key(folder string, cfg Config) (string, error) {
+	return ModuleHash(folder, cfg)
+}
+
+// ModuleHash returns the hash of the module in folder: the path of the folder, as positions are relative to it,
+// its go.mod, go.sum and Go files, outside of nested modules and testdata folders, and the configuration.
+// The modules replaced by a local directory in its go.mod are hashed the same way. Unless the configuration lists
+// platforms, the platform the API is read for, from the GOOS and GOARCH environment variables, is hashed too.
+// Other dependencies are identified by the go.sum file, and go.work files are ignored.
+func ModuleHash(folder string, cfg Config) (string, error) {
+	h := sha256.New()
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	_, _ = io.WriteString(h, cacheVersion+"\x00"+filepath.ToSlash(folder)+"\x00")
+	_, _ = h.Write(cfgJSON)
+	if len(cfg.Platforms) == 0 {
+		_, _ = io.WriteString(h, "\x00"+cmp.Or(os.Getenv("GOOS"), runtime.GOOS)+"/"+cmp.Or(os.Getenv("GOARCH"), runtime.GOARCH))
+	}
+	if err = hashModuleFiles(h, folder, ""); err != nil {
+		return "", err
+	}
+	replaced, err := localReplacements(folder)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range replaced {
+		path := dir
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(folder, dir)
+		}
+		if err = hashModuleFiles(h, path, filepath.ToSlash(dir)+"\x00"); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashModuleFiles writes the go.mod, go.sum and Go files of the module in folder to h, prefixing their paths.
+func hashModuleFiles(h io.Writer, folder string, prefix string) error {
+	return filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == folder {
+				return nil
+			}
+			if d.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" && path != filepath.Join(folder, "go.mod") && path != filepath.Join(folder, "go.sum") {
+			return nil
+		}
+		b, err := os.ReadFile(path) // #nosec G304
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		_, _ = io.WriteString(h, "\x00"+prefix+filepath.ToSlash(rel)+"\x00")
+		_, _ = h.Write(b)
+		return nil
+	})
+}
+
+// localReplacements returns the directories of the modules replaced by a local directory in the go.mod file
+// of the module in folder, as written in the go.mod file.
+func localReplacements(folder string) ([]string, error) {
+	gomod := filepath.Join(folder, "go.mod")
+	b, err := os.ReadFile(gomod) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.Parse(gomod, b, nil)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, r := range f.Replace {
+		if r.New.Version != "" {
+			continue
+		}
+		dirs = append(dirs, filepath.FromSlash(r.New.Path))
+	}
+	return dirs, nil
+}
+
+// Get returns the API stored under key, if any.
+func (c *Cache) Get(key string) (CachedAPI, bool, error) {
+	b, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return CachedAPI{}, false, nil
+	}
+	if err != nil {
+		return CachedAPI{}, false, err
+	}
+	var cached CachedAPI
+	if err = json.Unmarshal(b, &cached); err != nil {
+		// a corrupted entry is read again from the module.
+		return CachedAPI{}, false, nil
+	}
+	return cached, true, nil
+}
+
+// Put stores the API under key. The entry is written to a temporary file first,
+// so that concurrent runs never read a partial entry.
+func (c *Cache) Put(key string, cached CachedAPI) error {
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(c.dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":              "module example.com/mod\n\nreplace example.com/dep => ./dep\n",
+		"config.go":           "package mod\n",
+		"sub/sub.go":          "package sub\n",
+		"README.md":           "# mod\n",
+		"nested/go.mod":       "module example.com/mod/nested\n",
+		"nested/nested.go":    "package nested\n",
+		"testdata/fixture":    "fixture\n",
+		"testdata/fixture.go": "package fixture\n",
+		"dep/go.mod":          "module example.com/dep\n",
+		"dep/dep.go":          "package dep\n",
+	})
+	c := NewCache(t.TempDir())
+	key, err := c.Key(dir, Config{})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name    string
+		files   map[string]string
+		cfg     Config
+		changed bool
+	}{
+		{name: "unchanged"},
+		{name: "other files", files: map[string]string{"README.md": "# changed\n", "nested/nested.go": "package nested\n\nconst A = 1\n", "testdata/fixture.go": "package changed\n"}},
+		{name: "go file", files: map[string]string{"sub/sub.go": "package sub\n\nconst A = 1\n"}, changed: true},
+		{name: "new go file", files: map[string]string{"sub/other.go": "package sub\n"}, changed: true},
+		{name: "go.mod", files: map[string]string{"go.mod": "module example.com/mod\n\ngo 1.25.0\n"}, changed: true},
+		{name: "replaced module", files: map[string]string{"dep/dep.go": "package dep\n\nconst A = 1\n"}, changed: true},
+		{name: "config", cfg: Config{ExcludedFiles: []string{"*_test.go"}}, changed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			moduleDir := t.TempDir()
+			require.NoError(t, os.CopyFS(moduleDir, os.DirFS(dir)))
+			writeFiles(t, moduleDir, tt.files)
+			t.Chdir(moduleDir)
+			otherKey, err := c.Key(".", tt.cfg)
+			require.NoError(t, err)
+			t.Chdir(dir)
+			baseKey, err := c.Key(".", Config{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.changed, otherKey != baseKey)
+		})
+	}
+
+	t.Chdir(dir)
+	relativeKey, err := c.Key(".", Config{})
+	require.NoError(t, err)
+	assert.NotEqual(t, key, relativeKey, "positions are relative to the folder")
+
+	t.Setenv("GOOS", "plan9")
+	otherPlatformKey, err := c.Key(".", Config{})
+	require.NoError(t, err)
+	assert.NotEqual(t, relativeKey, otherPlatformKey, "the API depends on the platform")
+	platformsKey, err := c.Key(".", Config{Platforms: []string{"linux/amd64"}})
+	require.NoError(t, err)
+	t.Setenv("GOOS", "windows")
+	otherPlatformsKey, err := c.Key(".", Config{Platforms: []string{"linux/amd64"}})
+	require.NoError(t, err)
+	assert.Equal(t, platformsKey, otherPlatformsKey, "the configured platforms do not depend on the environment")
+}
+
+func TestCacheGetPut(t *testing.T) {
+	c := NewCache(filepath.Join(t.TempDir(), "cache"))
+	_, ok, err := c.Get("key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	cached := CachedAPI{
+		API: API{
+			Values:    []APIvalue{{Name: "Foo", Kind: ValueKindVar, Type: "int", Pos: Position{File: "foo.go", Line: 1, Column: 5}}},
+			Functions: []Function{{Name: "NewFactory", ReturnTypes: []string{"receiver.Factory"}}},
+			Enums:     map[string][]string{"Mode": {"a"}},
+		},
+		PlatformSpecific: []PlatformSpecificDeclaration{{Kind: "function", Name: "NewFactory", Platforms: []string{"linux/amd64"}}},
+	}
+	require.NoError(t, c.Put("key", cached))
+	read, ok, err := c.Get("key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, cached, read)
+
+	require.NoError(t, os.WriteFile(c.path("corrupted"), []byte("{"), 0o600))
+	_, ok, err = c.Get("corrupted")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
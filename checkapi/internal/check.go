@@ -0,0 +1,527 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/goccy/go-json"
+	"github.com/kaptinlin/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadModule reads the API of the module in folder for the platforms of the configuration, if any,
+// and returns the union of their APIs and the exported declarations missing on some of them.
+// If cache is set, the API of modules whose Go files and configuration did not change is read from it.
+func ReadModule(cfg Config, folder string, cache *Cache) (API, []PlatformSpecificDeclaration, error) {
+	var key string
+	if cache != nil {
+		var err error
+		if key, err = cache.Key(folder, cfg); err != nil {
+			return API{}, nil, err
+		}
+		cached, ok, err := cache.Get(key)
+		if err != nil {
+			return API{}, nil, err
+		}
+		if ok {
+			return cached.API, cached.PlatformSpecific, nil
+		}
+	}
+	var api API
+	var platformSpecific []PlatformSpecificDeclaration
+	var err error
+	if len(cfg.Platforms) == 0 {
+		api, err = Read(folder, cfg.IgnoredFunctions, cfg.ExcludedFiles)
+	} else {
+		api, platformSpecific, err = ReadPlatforms(folder, cfg.IgnoredFunctions, cfg.ExcludedFiles, cfg.Platforms)
+	}
+	if err != nil {
+		return API{}, nil, err
+	}
+	if cache != nil {
+		if err = cache.Put(key, CachedAPI{API: api, PlatformSpecific: platformSpecific}); err != nil {
+			return API{}, nil, err
+		}
+	}
+	return api, platformSpecific, nil
+}
+
+// CheckModule checks the API of the module in folder against the rules of the configuration,
+// and returns the violations as diagnostics. version is the version of its module set, if known,
+// and modulePaths are the paths of the modules of the repository.
+func CheckModule(cfg Config, folder string, metadata Metadata, version string, modulePaths []string, result API, platformSpecific []PlatformSpecificDeclaration) ([]Diagnostic, error) {
+	sort.Slice(result.Structs, func(i int, j int) bool {
+		return strings.Compare(result.Structs[i].Name, result.Structs[j].Name) > 0
+	})
+	sort.Slice(result.Values, func(i int, j int) bool {
+		return strings.Compare(result.Values[i].Name, result.Values[j].Name) < 0
+	})
+	sort.Slice(result.Functions, func(i int, j int) bool {
+		return strings.Compare(result.Functions[i].Name, result.Functions[j].Name) < 0
+	})
+	fnNames := make([]string, len(result.Functions))
+	fnPositions := make(map[string]Position, len(result.Functions))
+	for i, fn := range result.Functions {
+		fnNames[i] = fn.Name
+		if _, ok := fnPositions[fn.Name]; !ok {
+			fnPositions[fn.Name] = fn.Pos
+		}
+	}
+	var diags []Diagnostic
+
+	if cfg.CrossModuleInternalImports.Enabled {
+		modulePath, err := ReadModulePath(folder)
+		if err != nil {
+			return nil, err
+		}
+		imports, err := CheckInternalImports(result, modulePath, modulePaths, cfg.CrossModuleInternalImports.Allowed)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] invalid allowed internal imports: %w", folder, err)
+		}
+		for _, imp := range imports {
+			diags = append(diags, NewDiagnostic(RuleCrossModuleImports, folder, imp.Pos, "internal package %q of module %q must not be imported from another module", imp.Path, imp.Module))
+		}
+	}
+
+	if len(result.Structs) == 0 && len(result.Interfaces) == 0 && len(result.Types) == 0 && len(result.Values) == 0 && len(result.Functions) == 0 {
+		// nothing else to validate, return
+		return diags, nil
+	}
+
+	if cfg.PlatformSpecificAPI {
+		for _, d := range platformSpecific {
+			diags = append(diags, NewDiagnostic(RulePlatformSpecificAPI, folder, d.Pos, "%s %q is only declared on %s", d.Kind, d.Name, strings.Join(d.Platforms, ",")))
+		}
+	}
+
+	if len(cfg.AllowedFunctions) > 0 {
+
+		functionsPresent := map[string]struct{}{}
+		functionsRemaining := make(map[string]struct{}, len(result.Functions))
+		for _, fn := range result.Functions {
+			if !fn.Internal {
+				functionsRemaining[fn.Name] = struct{}{}
+			}
+		}
+		for _, fnDesc := range cfg.AllowedFunctions {
+			if !slices.Contains(fnDesc.Classes, metadata.Status.Class) {
+				continue
+			}
+			// any function
+			if fnDesc.Name == "*" {
+				functionsPresent[""] = struct{}{}
+				functionsRemaining = map[string]struct{}{}
+				break
+			}
+			// no functions at all.
+			if fnDesc.Name == "" {
+				functionsPresent[""] = struct{}{}
+				functionsRemaining = map[string]struct{}{}
+				fnNames = make([]string, 0, len(result.Functions))
+				for _, fn := range result.Functions {
+					if !fn.Internal {
+						fnNames = append(fnNames, fn.Name)
+					}
+				}
+				for _, name := range fnNames {
+					diags = append(diags, NewDiagnostic(RuleAllowedFunctions, folder, fnPositions[name], "no function must be exported under this module, found %q", name))
+				}
+				break
+			}
+
+			for _, fn := range result.Functions {
+				matches, err := fnDesc.Matches(fn)
+				if err != nil {
+					return nil, fmt.Errorf("[%s] invalid allowed function %q: %w", folder, fnDesc.Name, err)
+				}
+				if matches {
+					functionsPresent[fn.Name] = struct{}{}
+					delete(functionsRemaining, fn.Name)
+				}
+			}
+		}
+
+		if len(functionsPresent) == 0 {
+			diags = append(diags, NewDiagnostic(RuleAllowedFunctions, folder, Position{}, "no function matching configuration found"))
+		}
+
+		if len(functionsRemaining) > 0 {
+			names := make([]string, 0, len(functionsRemaining))
+			for fnName := range functionsRemaining {
+				names = append(names, fnName)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				diags = append(diags, NewDiagnostic(RuleAllowedFunctions, folder, fnPositions[name], "function %q should not be exported", name))
+			}
+		}
+	}
+
+	diags = append(diags, checkAllowedTypes(cfg.AllowedTypes, result, metadata.Status.Class, folder)...)
+
+	if cfg.UnkeyedLiteral.Enabled {
+		for _, s := range result.Structs {
+			if d, ok := checkStructDisallowUnkeyedLiteral(cfg, s, folder); ok {
+				diags = append(diags, d)
+			}
+		}
+	}
+
+	if cfg.ExportedVariables.Enabled {
+		values, err := MutableValues(result, cfg.ExportedVariables)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] invalid exported variables configuration: %w", folder, err)
+		}
+		for _, v := range values {
+			diags = append(diags, NewDiagnostic(RuleExportedVariables, folder, v.Pos, "variable %q of type %q must not be exported, as it can be modified by any importer", v.Name, v.Type))
+		}
+	}
+
+	if cfg.UnexportedReturnTypes || cfg.UnexportedParameterTypes {
+		for _, fn := range result.Functions {
+			if fn.Internal {
+				continue
+			}
+			name := fn.Name
+			if fn.Receiver != "" {
+				name = fn.Receiver + "." + fn.Name
+			}
+			if types := UnexportedTypes(fn.ReturnTypes, fn.TypeParamNames); cfg.UnexportedReturnTypes && len(types) > 0 {
+				diags = append(diags, NewDiagnostic(RuleUnexportedReturns, folder, fn.Pos, "exported function %q returns unexported types %q", name, strings.Join(types, ",")))
+			}
+			if types := UnexportedTypes(fn.Params, fn.TypeParamNames); cfg.UnexportedParameterTypes && len(types) > 0 {
+				diags = append(diags, NewDiagnostic(RuleUnexportedParameters, folder, fn.Pos, "exported function %q takes unexported types %q", name, strings.Join(types, ",")))
+			}
+		}
+	}
+
+	if cfg.Deprecations.Enabled {
+		for _, issue := range CheckDeprecations(result, cfg.Deprecations.MaxMinorReleases, version) {
+			diags = append(diags, NewDiagnostic(RuleDeprecations, folder, issue.Pos, "%s", issue.Message))
+		}
+	}
+
+	if (!cfg.JSONSchema.CheckPresent && !cfg.JSONSchema.CheckValid && !cfg.JSONSchema.CheckDefaults && !cfg.ComponentAPI && !cfg.ComponentAPIStrict && !cfg.EmbeddedConfigFields.Enabled && !cfg.ConfigConventions.Enabled) || !IsFactoryComponent(metadata.Status.Class) {
+		return diags, nil
+	}
+
+	if result.ConfigStructName == "" && cfg.ComponentAPIStrict {
+		diags = append(diags, NewDiagnostic(RuleComponentAPI, folder, Position{}, "cannot find the createDefaultConfig function"))
+		return diags, nil
+	}
+
+	var cfgStruct *APIstruct
+	allStructs := make(map[string]struct{}, len(result.Structs))
+	structsByName := make(map[string]APIstruct, len(result.Structs))
+	for _, s := range result.Structs {
+		segments := strings.Split(s.Name, ".")
+		name := segments[len(segments)-1]
+		if ast.IsExported(name) {
+			allStructs[s.Name] = struct{}{}
+			structsByName[s.Name] = s
+		}
+		if s.Name == result.ConfigStructName {
+			cfgStruct = &s
+		}
+	}
+	if cfgStruct == nil {
+		if cfg.ComponentAPIStrict {
+			diags = append(diags, NewDiagnostic(RuleComponentAPI, folder, Position{}, "cannot find the config struct"))
+		}
+		return diags, nil
+	}
+
+	if cfg.EmbeddedConfigFields.Enabled {
+		diags = append(diags, checkNoEmbeddedConfigFields(cfg.EmbeddedConfigFields, structsByName, *cfgStruct, folder, map[string]struct{}{})...)
+	}
+
+	if cfg.ConfigConventions.Enabled {
+		if !slices.ContainsFunc(result.Methods[cfgStruct.Name], isValidateMethod) {
+			diags = append(diags, NewDiagnostic(RuleConfigConventions, folder, cfgStruct.Pos, "config struct %q must have a Validate() error method", cfgStruct.Name))
+		}
+		diags = append(diags, checkConfigConventions(structsByName, *cfgStruct, folder, map[string]struct{}{})...)
+	}
+
+	metadataPos := Position{File: filepath.Join(folder, "metadata.yaml")}
+	if metadata.Config != nil && (cfg.JSONSchema.CheckValid || cfg.JSONSchema.CheckDefaults) {
+		configSchema, err := CompileSchema(metadata.Config)
+		switch {
+		case err != nil:
+			diags = append(diags, NewDiagnostic(RuleJSONSchema, folder, metadataPos, "%v", err))
+		case cfg.JSONSchema.CheckDefaults:
+			defaults := ConfigDefaultKeys(result.ConfigDefaults, *cfgStruct, result.Structs)
+			for _, e := range CompareConfigDefaults(defaults, configSchema) {
+				diags = append(diags, NewDiagnostic(RuleJSONSchema, folder, metadataPos, "%v", e))
+			}
+		}
+		if err == nil && cfg.JSONSchema.CheckValid {
+			var structDerivedSchema *jsonschema.Schema
+			if structDerivedSchema, err = DeriveSchema(*cfgStruct, result, cfg.JSONSchema.TypeMappings); err != nil {
+				return nil, err
+			} else if err := CompareJSONSchema(configSchema, structDerivedSchema); err != nil {
+				for _, e := range flattenErrors(err) {
+					diags = append(diags, NewDiagnostic(RuleJSONSchema, folder, metadataPos, "%v", e))
+				}
+				rawJSON, _ := SchemaToYAML(structDerivedSchema)
+				configSchemaYAML, _ := yaml.Marshal(rawJSON)
+				diags = append(diags, NewDiagnostic(RuleJSONSchema, folder, metadataPos, "new JSON schema: %s", string(configSchemaYAML)))
+			}
+		}
+	}
+
+	if cfg.ComponentAPIStrict || cfg.ComponentAPI {
+		delete(allStructs, cfgStruct.Name)
+		filterStructs(structsByName, *cfgStruct, allStructs)
+		for k, v := range structsByName {
+			if v.Internal {
+				delete(allStructs, k)
+			}
+		}
+		if len(allStructs) > 0 {
+			structNames := make([]string, 0, len(allStructs))
+			for k := range allStructs {
+				structNames = append(structNames, k)
+			}
+			sort.Strings(structNames)
+			for _, name := range structNames {
+				diags = append(diags, NewDiagnostic(RuleComponentAPI, folder, structsByName[name].Pos, "struct %q is not part of config and cannot be exported", name))
+			}
+		}
+	}
+
+	return diags, nil
+}
+
+// IsFactoryComponent reports whether components of the class are created by a factory from a config struct.
+func IsFactoryComponent(class string) bool {
+	return class == "connector" || class == "exporter" || class == "extension" || class == "processor" || class == "receiver"
+}
+
+// CompileSchema compiles the JSON schema read from the config section of a metadata.yaml file.
+func CompileSchema(config any) (*jsonschema.Schema, error) {
+	configSchemaBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.NewCompiler().Compile(configSchemaBytes)
+}
+
+// flattenErrors returns the errors joined in err, or err itself.
+func flattenErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// checkAllowedTypes reports the exported structs, interfaces and named types that are not allowed for the class of the component.
+// Types are only checked if at least one rule applies to the class. A rule named "*" allows any type,
+// a rule without a name allows none.
+func checkAllowedTypes(allowedTypes []TypeDescription, result API, class string, folder string) []Diagnostic {
+	applies := false
+	allowed := map[string]struct{}{}
+	for _, typeDesc := range allowedTypes {
+		if !slices.Contains(typeDesc.Classes, class) {
+			continue
+		}
+		if typeDesc.Name == "*" {
+			return nil
+		}
+		applies = true
+		if typeDesc.Name != "" {
+			allowed[typeDesc.Name] = struct{}{}
+		}
+	}
+	if !applies {
+		return nil
+	}
+
+	var names []string
+	positions := map[string]Position{}
+	check := func(name string, isInternal bool, pos Position) {
+		segments := strings.Split(name, ".")
+		if isInternal || !ast.IsExported(segments[len(segments)-1]) {
+			return
+		}
+		if _, ok := allowed[name]; !ok {
+			names = append(names, name)
+			positions[name] = pos
+		}
+	}
+	for _, s := range result.Structs {
+		check(s.Name, s.Internal, s.Pos)
+	}
+	for _, i := range result.Interfaces {
+		check(i.Name, i.Internal, i.Pos)
+	}
+	for _, t := range result.Types {
+		check(t.Name, t.Internal, t.Pos)
+	}
+	sort.Strings(names)
+	diags := make([]Diagnostic, 0, len(names))
+	for _, name := range names {
+		diags = append(diags, NewDiagnostic(RuleAllowedTypes, folder, positions[name], "type %q should not be exported", name))
+	}
+	return diags
+}
+
+func filterStructs(structMap map[string]APIstruct, current APIstruct, allStructs map[string]struct{}) {
+	for _, f := range current.Fields {
+		if s, ok := structMap[f.Type]; ok {
+			delete(allStructs, s.Name)
+			filterStructs(structMap, s, allStructs)
+		}
+	}
+}
+
+// checkNoEmbeddedConfigFields reports the embedded (anonymous) fields of the config struct and of
+// every struct reachable from it.
+func checkNoEmbeddedConfigFields(cfg EmbeddedConfigFields, structMap map[string]APIstruct, current APIstruct, folder string, visited map[string]struct{}) []Diagnostic {
+	if _, seen := visited[current.Name]; seen {
+		return nil
+	}
+	visited[current.Name] = struct{}{}
+
+	var embedded []string
+	for _, f := range current.Fields {
+		if f.Name == "" && !slices.Contains(cfg.IgnoredTypes, f.Type) && !slices.Contains(cfg.IgnoredTypes, ShortTypeString(f.Type)) {
+			embedded = append(embedded, f.Type)
+		}
+	}
+	var diags []Diagnostic
+	for _, name := range embedded {
+		diags = append(diags, NewDiagnostic(RuleEmbeddedConfigFields, folder, current.Pos, "config struct %q must not have embedded fields, found %q", current.Name, name))
+	}
+	for _, f := range current.Fields {
+		if s, ok := structMap[f.Type]; ok {
+			diags = append(diags, checkNoEmbeddedConfigFields(cfg, structMap, s, folder, visited)...)
+		}
+	}
+	return diags
+}
+
+// checkConfigConventions reports the exported fields without a mapstructure tag, and the mapstructure keys
+// that are not lower_snake_case or not unique once squashed fields are flattened, of the config struct
+// and of every struct reachable from it.
+func checkConfigConventions(structMap map[string]APIstruct, current APIstruct, folder string, visited map[string]struct{}) []Diagnostic {
+	if _, seen := visited[current.Name]; seen {
+		return nil
+	}
+	visited[current.Name] = struct{}{}
+
+	var untagged []string
+	for i, f := range current.Fields {
+		// map and generic fields are recorded once for each type they hold.
+		if i > 0 && f.Name != "" && current.Fields[i-1].Name == f.Name {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = f.Type[strings.LastIndex(f.Type, ".")+1:]
+		}
+		if _, _, ok := ParseTag(f.Tag); !ok && ast.IsExported(name) {
+			untagged = append(untagged, name)
+		}
+	}
+	var diags []Diagnostic
+	for _, name := range untagged {
+		diags = append(diags, NewDiagnostic(RuleConfigConventions, folder, current.Pos, "field %q of config struct %q must have a mapstructure tag", name, current.Name))
+	}
+	var invalid, duplicates []string
+	seen := map[string]struct{}{}
+	for _, key := range mapstructureKeys(structMap, current, map[string]struct{}{}) {
+		if !snakeCasePattern.MatchString(key) && !slices.Contains(invalid, key) {
+			invalid = append(invalid, key)
+		}
+		// mapstructure matches keys regardless of their case.
+		if _, ok := seen[strings.ToLower(key)]; ok && !slices.Contains(duplicates, key) {
+			duplicates = append(duplicates, key)
+		}
+		seen[strings.ToLower(key)] = struct{}{}
+	}
+	for _, key := range invalid {
+		diags = append(diags, NewDiagnostic(RuleConfigConventions, folder, current.Pos, "mapstructure key %q of config struct %q must be lower_snake_case", key, current.Name))
+	}
+	for _, key := range duplicates {
+		diags = append(diags, NewDiagnostic(RuleConfigConventions, folder, current.Pos, "mapstructure key %q of config struct %q is not unique once squashed fields are flattened", key, current.Name))
+	}
+	for _, f := range current.Fields {
+		if s, ok := structMap[f.Type]; ok {
+			diags = append(diags, checkConfigConventions(structMap, s, folder, visited)...)
+		}
+	}
+	return diags
+}
+
+// snakeCasePattern matches lower_snake_case mapstructure keys.
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// mapstructureKeys returns the mapstructure keys of the fields of a struct, including the ones of its squashed fields
+// that are declared in the module. Fields without a name in their tag are keyed by their name.
+func mapstructureKeys(structMap map[string]APIstruct, s APIstruct, visited map[string]struct{}) []string {
+	if _, seen := visited[s.Name]; seen {
+		return nil
+	}
+	visited[s.Name] = struct{}{}
+	var keys []string
+	for i, f := range s.Fields {
+		if i > 0 && f.Name != "" && s.Fields[i-1].Name == f.Name {
+			continue
+		}
+		name, options, ok := ParseTag(f.Tag)
+		switch {
+		case !ok || name == "-" || slices.Contains(options, "remain"):
+		case slices.Contains(options, "squash"):
+			if squashed, ok := structMap[f.Type]; ok {
+				keys = append(keys, mapstructureKeys(structMap, squashed, visited)...)
+			}
+		case name != "":
+			keys = append(keys, name)
+		case f.Name != "":
+			keys = append(keys, f.Name)
+		}
+	}
+	return keys
+}
+
+// isValidateMethod reports whether a method is the Validate() error method of config structs.
+func isValidateMethod(fn Function) bool {
+	return fn.Name == "Validate" && len(fn.Params) == 0 && slices.Equal(fn.ReturnTypes, []string{"error"})
+}
+
+func checkStructDisallowUnkeyedLiteral(cfg Config, s APIstruct, folder string) (Diagnostic, bool) {
+	if s.Internal {
+		return Diagnostic{}, false
+	}
+	if !unicode.IsUpper(rune(s.Name[0])) {
+		return Diagnostic{}, false
+	}
+	if len(s.Fields) > cfg.UnkeyedLiteral.Limit {
+		return Diagnostic{}, false
+	}
+	if len(s.Fields) == 0 {
+		return Diagnostic{}, false
+	}
+
+	for _, f := range s.Fields {
+		if len(f.Name) == 0 {
+			if !unicode.IsUpper(rune(f.Type[0])) {
+				return Diagnostic{}, false
+			}
+		} else {
+			if !unicode.IsUpper(rune(f.Name[0])) {
+				return Diagnostic{}, false
+			}
+		}
+	}
+	return NewDiagnostic(RuleUnkeyedLiteral, folder, s.Pos, "struct %q does not prevent unkeyed literal initialization", s.Name), true
+}
@@ -3,6 +3,12 @@
 
 package internal
 
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Function represents a function in the codebase.
 type Function struct {
 	Name        string   `json:"name"`
@@ -10,29 +16,106 @@ type Function struct {
 	ReturnTypes []string `json:"return_types,omitempty"`
 	Params      []string `json:"params,omitempty"`
 	TypeParams  []string `json:"type_params,omitempty"`
-	Internal    bool     `json:"internal"`
+	// TypeParamNames are the names of the type parameters of the function and of its receiver type.
+	TypeParamNames []string `json:"type_param_names,omitempty"`
+	Internal       bool     `json:"internal,omitempty"`
+	Pos            Position `json:"pos,omitzero"`
 }
 
 // APIstructField represents a struct field in the codebase.
 type APIstructField struct {
-	Name string
-	Type string
-	Tag  string
+	Name string `json:"name"`
+	// Type is the element type of pointers, slices, arrays, maps, channels and generic types,
+	// or the type of the field otherwise.
+	Type string `json:"type"`
+	// FullType is the type of the field as declared, if it differs from Type.
+	FullType string `json:"full_type,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	// Doc is the documentation of the field, from its doc comment or its line comment.
+	Doc string `json:"doc,omitempty"`
 }
 
 // APIstruct represents a struct in the codebase.
 type APIstruct struct {
-	Name     string           `json:"name"`
-	Fields   []APIstructField `json:"fields"`
-	Internal bool             `json:"internal"`
+	Name string `json:"name"`
+	// TypeParams are the names of the type parameters of a generic struct.
+	TypeParams []string         `json:"type_params,omitempty"`
+	Fields     []APIstructField `json:"fields"`
+	Internal   bool             `json:"internal,omitempty"`
+	Pos        Position         `json:"pos,omitzero"`
+}
+
+// APIinterface represents an interface in the codebase.
+type APIinterface struct {
+	Name     string     `json:"name"`
+	Methods  []Function `json:"methods,omitempty"`
+	Embedded []string   `json:"embedded,omitempty"`
+	Internal bool       `json:"internal,omitempty"`
+	Pos      Position   `json:"pos,omitzero"`
+}
+
+const (
+	// ValueKindConst is the kind of constants.
+	ValueKindConst = "const"
+	// ValueKindVar is the kind of variables.
+	ValueKindVar = "var"
+)
+
+// APIvalue represents a package-level constant or variable in the codebase.
+type APIvalue struct {
+	Name string `json:"name"`
+	// Kind is either "const" or "var".
+	Kind string `json:"kind,omitempty"`
+	// Type is the type of the value, as "untyped string" for untyped constants.
+	Type     string   `json:"type,omitempty"`
+	Internal bool     `json:"internal,omitempty"`
+	Pos      Position `json:"pos,omitzero"`
+}
+
+// APItype represents a named type that is neither a struct nor an interface, or a type alias.
+type APItype struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Alias    bool     `json:"alias,omitempty"`
+	Internal bool     `json:"internal,omitempty"`
+	Pos      Position `json:"pos,omitzero"`
 }
 
-// API represents the API of the codebase, including functions and structs.
+// API represents the API of the codebase, including functions, structs, interfaces and other named types.
 type API struct {
-	Values           []string    `json:"values,omitempty"`
-	Structs          []APIstruct `json:"structs,omitempty"`
-	Functions        []Function  `json:"functions,omitempty"`
-	ConfigStructName string
+	Values           []APIvalue     `json:"values,omitempty"`
+	Structs          []APIstruct    `json:"structs,omitempty"`
+	Interfaces       []APIinterface `json:"interfaces,omitempty"`
+	Types            []APItype      `json:"types,omitempty"`
+	Functions        []Function     `json:"functions,omitempty"`
+	ConfigStructName string         `json:"config_struct_name,omitempty"`
+	// Enums maps the named string types of the module to the values of the constants declared with them.
+	Enums map[string][]string `json:"enums,omitempty"`
+	// Deprecations are the exported declarations documented as deprecated.
+	Deprecations []Deprecation `json:"deprecations,omitempty"`
+	// Methods maps the named types of the module to the signatures of all their methods, exported or not.
+	Methods map[string][]Function `json:"methods,omitempty"`
+	// Imports are the imports of the Go files of the module, including test files.
+	Imports []APIimport `json:"imports,omitempty"`
+	// ConfigDefaults are the values of the fields of the config returned by createDefaultConfig
+	// that could be evaluated statically, keyed by field name.
+	ConfigDefaults map[string]any `json:"config_defaults,omitempty"`
+}
+
+// APIimport represents an import of a Go file of the module.
+type APIimport struct {
+	Path string   `json:"path"`
+	Pos  Position `json:"pos,omitzero"`
+}
+
+// Deprecation represents an exported declaration documented as deprecated.
+type Deprecation struct {
+	// Name is the name of the declaration, prefixed with the name of its type for methods and struct fields.
+	Name string `json:"name"`
+	// Notice is the paragraph of the doc comment starting with "Deprecated:".
+	Notice   string   `json:"notice"`
+	Internal bool     `json:"internal,omitempty"`
+	Pos      Position `json:"pos,omitzero"`
 }
 
 // FunctionDescription represents a function description.
@@ -43,17 +126,96 @@ type FunctionDescription struct {
 	ReturnTypes []string `yaml:"return_types"`
 }
 
+// TypeDescription represents a type that may be exported by a class of components.
+type TypeDescription struct {
+	Classes []string `yaml:"classes"`
+	Name    string   `yaml:"name"`
+}
+
 // Config represents the configuration for the codebase analysis.
 type Config struct {
 	IgnoredPaths         []string              `yaml:"ignored_paths"`
 	ExcludedFiles        []string              `yaml:"excluded_files"`
 	AllowedFunctions     []FunctionDescription `yaml:"allowed_functions"`
 	IgnoredFunctions     []string              `yaml:"ignored_functions"`
+	AllowedTypes         []TypeDescription     `yaml:"allowed_types"`
 	UnkeyedLiteral       UnkeyedLiteral        `yaml:"unkeyed_literal_initialization"`
 	ComponentAPI         bool                  `yaml:"component_api"`
 	ComponentAPIStrict   bool                  `yaml:"component_api_strict"`
 	JSONSchema           JSONSchemaConfig      `yaml:"json_schema"`
 	EmbeddedConfigFields EmbeddedConfigFields  `yaml:"embedded_config_fields"`
+	BreakingChanges      BreakingChangesConfig `yaml:"breaking_changes"`
+	Deprecations         DeprecationsConfig    `yaml:"deprecations"`
+	ExportedVariables    ExportedVariables     `yaml:"exported_variables"`
+	// UnexportedReturnTypes forbids exported functions returning unexported types.
+	UnexportedReturnTypes bool `yaml:"unexported_return_types"`
+	// UnexportedParameterTypes forbids exported functions taking unexported types as parameters.
+	UnexportedParameterTypes bool `yaml:"unexported_parameter_types"`
+	// Platforms are the target platforms, written GOOS/GOARCH, the API of each module is read for.
+	// The checks run on the union of their APIs. If empty, the API is read for the current platform.
+	Platforms []string `yaml:"platforms"`
+	// PlatformSpecificAPI forbids exported declarations missing on some of the platforms.
+	PlatformSpecificAPI bool              `yaml:"platform_specific_api"`
+	ConfigConventions   ConfigConventions `yaml:"config_conventions"`
+	// CrossModuleInternalImports forbids imports of the internal packages of the other modules of the repository.
+	CrossModuleInternalImports CrossModuleInternalImports `yaml:"cross_module_internal_imports"`
+}
+
+// ConfigConventions represents the conventions checked on the config struct of components
+// and on every struct reachable from it: exported fields have a mapstructure tag, mapstructure keys are
+// lower_snake_case and unique once squashed fields are flattened, and the config struct has a Validate() error method.
+type ConfigConventions struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ExportedVariables represents the configuration of the check forbidding exported mutable variables.
+type ExportedVariables struct {
+	Enabled bool `yaml:"enabled"`
+	// AllowedTypes are the types, or type patterns, of the variables that may be exported, as "error" for sentinel errors.
+	AllowedTypes []string `yaml:"allowed_types"`
+	// AllowedNames are the names, or name patterns, of the variables that may be exported.
+	AllowedNames []string `yaml:"allowed_names"`
+}
+
+// DeprecationsConfig represents the configuration of the deprecation lifecycle check.
+type DeprecationsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxMinorReleases is the number of minor releases of the module set of a module
+	// after which its deprecated declarations must be removed. Zero never requires their removal.
+	MaxMinorReleases int `yaml:"max_minor_releases"`
+}
+
+// BreakingChangesConfig represents the policies applied to breaking API changes, based on the module set of a module.
+// Each policy is one of "error", "warn" or "ignore".
+type BreakingChangesConfig struct {
+	// Stable applies to modules of a module set with a version of v1 or above. Defaults to "error".
+	Stable string `yaml:"stable"`
+	// Unstable applies to modules of a v0 module set, or not part of any module set. Defaults to "warn".
+	Unstable string `yaml:"unstable"`
+	// ModuleSets overrides the policy of specific module sets, by name.
+	ModuleSets map[string]string `yaml:"module_sets"`
+	// Override replaces all the other policies. It is set when a module enables or disables the rule.
+	Override string `yaml:"-"`
+}
+
+// Policy returns the policy applied to the breaking changes of a module of the given module set and version.
+func (b BreakingChangesConfig) Policy(moduleSet string, version string) string {
+	if b.Override != "" {
+		return b.Override
+	}
+	if p, ok := b.ModuleSets[moduleSet]; ok {
+		return p
+	}
+	if moduleSet != "" && IsStableVersion(version) {
+		if b.Stable == "" {
+			return PolicyError
+		}
+		return b.Stable
+	}
+	if b.Unstable == "" {
+		return PolicyWarn
+	}
+	return b.Unstable
 }
 
 // EmbeddedConfigFields represents the configuration for the embedded config fields check.
@@ -62,11 +224,21 @@ type EmbeddedConfigFields struct {
 	IgnoredTypes []string `yaml:"ignored_types"`
 }
 
+// CrossModuleInternalImports represents the configuration of the check of imports of the internal packages of other modules.
+type CrossModuleInternalImports struct {
+	Enabled bool `yaml:"enabled"`
+	// Allowed are the import paths, or patterns, of the internal packages of other modules that may be imported.
+	Allowed []string `yaml:"allowed"`
+}
+
 // JSONSchemaConfig represents the configuration of JSON schema validation and mapping
 type JSONSchemaConfig struct {
 	CheckPresent bool              `yaml:"check_present"`
 	CheckValid   bool              `yaml:"check_valid"`
 	TypeMappings map[string]string `yaml:"type_mappings"`
+	// CheckDefaults compares the default values of the config, returned by createDefaultConfig,
+	// with the types, enums and default values of the JSON schema.
+	CheckDefaults bool `yaml:"check_defaults"`
 }
 
 // UnkeyedLiteral represents the configuration for unkeyed literal initialization.
@@ -74,3 +246,16 @@ type UnkeyedLiteral struct {
 	Enabled bool `yaml:"enabled"`
 	Limit   int  `yaml:"limit"`
 }
+
+// ReadConfig reads the configuration file at configPath.
+func ReadConfig(configPath string) (Config, error) {
+	configData, err := os.ReadFile(configPath) // #nosec G304
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err = yaml.Unmarshal(configData, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
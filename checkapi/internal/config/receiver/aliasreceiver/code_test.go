@@ -0,0 +1,19 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package aliasreceiver
+
+import (
+	embedded "github.com/open-telemetry/opentelemetry-go-build-tools/checkapi/internal/config/receiver/aliasreceiver/emb"
+	"go.opentelemetry.io/collector/component"
+)
+
+func createDefaultConfig() component.Config { // nolint:unused // we do need that method for tests
+	return &Config{}
+}
+
+type Config struct {
+	// Embedded struct, imported with an alias
+	embedded.Config `mapstructure:",squash"`
+	Timeout         string `mapstructure:"timeout"`
+}
@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package emb provides embedded configuration types for testing.
+package emb
+
+// Config holds the embedded configuration fields.
+type Config struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
@@ -40,6 +40,14 @@ type Config struct {
 	Holder GenericHolder[GenericType]
 	// Map holding types
 	MapOfStructs map[Key]Value
+	// Generic types mapped to the schema of their type argument
+	Optional Optional[SubConfig2] `mapstructure:"optional"`
+	// Slice of instantiated generic structs
+	Items []GenericHolder[SubConfig2] `mapstructure:"items"`
+	// Keyed by its name
+	Retries int `mapstructure:",omitempty"`
+	// Not decoded
+	Ignored string `mapstructure:"-"`
 }
 
 type Key struct{}
@@ -66,6 +74,10 @@ type EmbeddedPtr struct {
 }
 
 type GenericHolder[T any] struct {
+	Value T `mapstructure:"value"`
+}
+
+type Optional[T any] struct {
 	Value T
 }
 
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package conventionsreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+func createDefaultConfig() component.Config { // nolint:unused // we do need that method for tests
+	return &Config{}
+}
+
+type Config struct {
+	Endpoint string        `mapstructure:"endpoint"`
+	Timeout  time.Duration `mapstructure:"timeOut"`
+	Untagged string
+	Ignored  string `mapstructure:"-"`
+	Common   `mapstructure:",squash"`
+	Client   ClientConfig `mapstructure:"client"`
+	// unexported fields are not decoded, and need no tag.
+	internal string //nolint:unused
+}
+
+type Common struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+type ClientConfig struct {
+	Headers map[string]string `mapstructure:"headers"`
+	TLS     *TLSConfig
+}
+
+type TLSConfig struct {
+	CAFile string `mapstructure:"ca_file"`
+}
+
+func (c *ClientConfig) Validate() error {
+	return nil
+}
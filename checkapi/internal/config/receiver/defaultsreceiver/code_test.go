@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package defaultsreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const defaultEndpoint = "localhost:4317"
+
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSlow Mode = "slow"
+)
+
+func createDefaultConfig() component.Config { // nolint:unused // we do need that method for tests
+	return newDefaultConfig()
+}
+
+func newDefaultConfig() *Config { // nolint:unused // we do need that method for tests
+	cfg := &Config{
+		Endpoint:   defaultEndpoint,
+		Timeout:    30 * time.Second,
+		Interval:   time.Minute,
+		Mode:       "medium",
+		Retries:    3,
+		Enabled:    true,
+		Common:     Common{Name: "defaults"},
+		Client:     ClientConfig{Compression: "gzip", MaxIdle: 10},
+		Headers:    map[string]string{"x-key": "value"},
+		Computed:   time.Now().String(),
+		Unexported: "ignored",
+	}
+	return cfg
+}
+
+type Config struct {
+	Endpoint   string        `mapstructure:"endpoint"`
+	Timeout    time.Duration `mapstructure:"timeout"`
+	Interval   time.Duration `mapstructure:"interval"`
+	Mode       Mode          `mapstructure:"mode"`
+	Retries    int           `mapstructure:"retries"`
+	Enabled    bool          `mapstructure:"enabled"`
+	Common     `mapstructure:",squash"`
+	Client     ClientConfig      `mapstructure:"client"`
+	Headers    map[string]string `mapstructure:"headers"`
+	Computed   string            `mapstructure:"computed"`
+	Unexported string
+}
+
+type Common struct {
+	Name string `mapstructure:"name"`
+}
+
+type ClientConfig struct {
+	Compression string `mapstructure:"compression"`
+	MaxIdle     int    `mapstructure:"max_idle"`
+}
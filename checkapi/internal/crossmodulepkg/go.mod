@@ -0,0 +1,3 @@
+module foo/bar/crossmodulepkg
+
+go 1.25.0
@@ -0,0 +1,7 @@
+module foo/bar/crossmodulepkg/pkg
+
+go 1.25.0
+
+require foo/bar/crossmodulepkg v0.0.0
+
+replace foo/bar/crossmodulepkg => ../
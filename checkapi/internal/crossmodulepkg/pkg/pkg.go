@@ -0,0 +1,12 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkg
+
+import (
+	"foo/bar/crossmodulepkg/internal/allowed"
+	"foo/bar/crossmodulepkg/internal/shared"
+	"foo/bar/crossmodulepkg/pkg/internal/own"
+)
+
+const Names = allowed.Name + shared.Name + own.Name
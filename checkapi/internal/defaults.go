@@ -0,0 +1,308 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// evaluateConfig statically evaluates the config returned by a function, following the functions it delegates to
+// and the variables it returns. Structs and maps are evaluated as maps keyed by field name or key,
+// slices and arrays as slices, and constant expressions as strings, booleans and numbers.
+// time.Duration constants are evaluated as their string representation, as in "5s".
+// Values that cannot be evaluated statically, such as function calls, are left out.
+func (r *packageReader) evaluateConfig(fn *ast.FuncDecl) map[string]any {
+	if fn.Body == nil || len(fn.Body.List) == 0 {
+		return nil
+	}
+	ret, ok := fn.Body.List[len(fn.Body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	result := ret.Results[0]
+	if call, ok := result.(*ast.CallExpr); ok && r.info != nil {
+		if callee, ok := typeutil.Callee(r.info, call).(*types.Func); ok {
+			if decl, ok := r.funcDecls[callee]; ok && decl != fn {
+				return r.evaluateConfig(decl)
+			}
+		}
+	}
+	if id, ok := result.(*ast.Ident); ok {
+		result = identValue(id)
+	}
+	values, _ := r.evaluate(result).(map[string]any)
+	return values
+}
+
+// identValue returns the expression assigned to a variable declared as `x := ...` or `var x = ...`.
+func identValue(id *ast.Ident) ast.Expr {
+	if id.Obj == nil {
+		return nil
+	}
+	switch d := id.Obj.Decl.(type) {
+	case *ast.AssignStmt:
+		if len(d.Lhs) == 1 && len(d.Rhs) == 1 {
+			return d.Rhs[0]
+		}
+	case *ast.ValueSpec:
+		if len(d.Names) == 1 && len(d.Values) == 1 {
+			return d.Values[0]
+		}
+	}
+	return nil
+}
+
+// evaluate returns the value of an expression, or nil if it cannot be evaluated statically.
+func (r *packageReader) evaluate(expr ast.Expr) any {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *ast.ParenExpr:
+		return r.evaluate(e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return r.evaluate(e.X)
+		}
+	case *ast.CompositeLit:
+		return r.evaluateCompositeLit(e)
+	}
+	if r.info != nil {
+		if tv, ok := r.info.Types[expr]; ok && tv.Value != nil {
+			return constantValue(tv.Value, tv.Type)
+		}
+	}
+	// fall back to the syntax when the type checker could not evaluate the expression.
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constantValue(constant.MakeFromLiteral(e.Value, e.Kind, 0), nil)
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return nil
+}
+
+func (r *packageReader) evaluateCompositeLit(lit *ast.CompositeLit) any {
+	isList := false
+	if _, ok := lit.Type.(*ast.ArrayType); ok {
+		isList = true
+	}
+	if r.info != nil {
+		if t := r.info.TypeOf(lit); isValidType(t) {
+			switch t.Underlying().(type) {
+			case *types.Slice, *types.Array:
+				isList = true
+			}
+		}
+	}
+	if isList {
+		values := make([]any, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if v := r.evaluate(elt); v != nil {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	values := map[string]any{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			// unkeyed fields cannot be matched to their name without the type of the literal.
+			continue
+		}
+		var key string
+		switch k := kv.Key.(type) {
+		case *ast.Ident:
+			key = k.Name
+		default:
+			s, ok := r.evaluate(k).(string)
+			if !ok {
+				continue
+			}
+			key = s
+		}
+		if v := r.evaluate(kv.Value); v != nil {
+			values[key] = v
+		}
+	}
+	return values
+}
+
+// constantValue converts a constant to a string, a boolean, an int64 or a float64.
+func constantValue(v constant.Value, t types.Type) any {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Bool:
+		return constant.BoolVal(v)
+	case constant.Int:
+		n, ok := constant.Int64Val(v)
+		if !ok {
+			return nil
+		}
+		if t != nil && types.TypeString(t, nil) == "time.Duration" {
+			return time.Duration(n).String()
+		}
+		return n
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return f
+	}
+	return nil
+}
+
+// ConfigDefaultKeys converts the evaluated defaults of a config struct, keyed by field name,
+// to values keyed by mapstructure key, as the properties of the JSON schema derived from the struct.
+// The values of squashed fields are flattened, and fields without a mapstructure key are left out.
+func ConfigDefaultKeys(defaults map[string]any, s APIstruct, structs []APIstruct) map[string]any {
+	result := map[string]any{}
+	for i, f := range s.Fields {
+		// map and generic fields are recorded once for each type they hold, only the last one is kept.
+		if f.Name != "" && i+1 < len(s.Fields) && s.Fields[i+1].Name == f.Name {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = shortName(f.Type)
+		}
+		value, ok := defaults[name]
+		if !ok {
+			continue
+		}
+		key, options, ok := ParseTag(f.Tag)
+		if !ok {
+			continue
+		}
+		nested, isStruct := value.(map[string]any)
+		if fieldStruct := matchingStruct(strings.TrimPrefix(fullType(f), "*"), structs); isStruct && fieldStruct.Name != "" {
+			nested = ConfigDefaultKeys(nested, fieldStruct, structs)
+		}
+		switch {
+		case slices.Contains(options, "squash"):
+			maps.Copy(result, nested)
+		case key == "" || key == "-":
+		case isStruct:
+			result[key] = nested
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// CompareConfigDefaults compares the default values of a config, keyed by mapstructure key,
+// with the types, enums and default values of the properties of its JSON schema.
+// Properties without a default value in the config are not compared.
+func CompareConfigDefaults(defaults map[string]any, schema *jsonschema.Schema) []error {
+	return compareDefaults("", defaults, schema)
+}
+
+func compareDefaults(prefix string, defaults map[string]any, schema *jsonschema.Schema) []error {
+	if schema == nil || schema.Properties == nil {
+		return nil
+	}
+	var errs []error
+	keys := make([]string, 0, len(defaults))
+	for k := range defaults {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := defaults[key]
+		prop, ok := (*schema.Properties)[key]
+		if !ok || prop == nil {
+			continue
+		}
+		path := prefix + key
+		if nested, ok := value.(map[string]any); ok {
+			errs = append(errs, compareDefaults(path+".", nested, prop)...)
+		}
+		if len(prop.Type) > 0 && !slices.ContainsFunc(prop.Type, func(t string) bool { return hasJSONType(value, t) }) {
+			errs = append(errs, fmt.Errorf("default value %v of field %q does not match its type %q", value, path, []string(prop.Type)))
+			continue
+		}
+		if len(prop.Enum) > 0 && !slices.ContainsFunc(prop.Enum, func(e any) bool { return equalDefaults(e, value) }) {
+			errs = append(errs, fmt.Errorf("default value %v of field %q is not one of its enum values %v", value, path, prop.Enum))
+		}
+		if prop.Default != nil && !equalDefaults(prop.Default, value) {
+			errs = append(errs, fmt.Errorf("default value %v of field %q differs from its default %v in the schema", value, path, prop.Default))
+		}
+	}
+	return errs
+}
+
+// hasJSONType reports whether an evaluated value is of a JSON schema type.
+func hasJSONType(value any, jsonType string) bool {
+	switch v := value.(type) {
+	case string:
+		return jsonType == "string"
+	case bool:
+		return jsonType == "boolean"
+	case int64:
+		return jsonType == "integer" || jsonType == "number"
+	case float64:
+		return jsonType == "number" || jsonType == "integer" && v == math.Trunc(v)
+	case map[string]any:
+		return jsonType == "object"
+	case []any:
+		return jsonType == "array"
+	}
+	return true
+}
+
+// equalDefaults reports whether a value of the schema equals an evaluated value.
+// Numbers are compared regardless of their type, and durations regardless of their unit, as "1m" and "60s".
+func equalDefaults(schemaValue any, value any) bool {
+	if a, ok := toFloat(schemaValue); ok {
+		b, ok := toFloat(value)
+		return ok && a == b
+	}
+	if a, ok := schemaValue.(string); ok {
+		b, ok := value.(string)
+		if !ok {
+			return false
+		}
+		if a == b {
+			return true
+		}
+		da, errA := time.ParseDuration(a)
+		db, errB := time.ParseDuration(b)
+		return errA == nil && errB == nil && da == db
+	}
+	return fmt.Sprint(schemaValue) == fmt.Sprint(value)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
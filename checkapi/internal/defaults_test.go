@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareConfigDefaults(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		defaults map[string]any
+		schema   *jsonschema.Schema
+		errors   []string
+	}{
+		{
+			name:     "matching default",
+			defaults: map[string]any{"foo": "bar"},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", jsonschema.String(jsonschema.Default("bar")))),
+		},
+		{
+			name:     "property missing from the schema",
+			defaults: map[string]any{"foo": "bar"},
+			schema:   jsonschema.Object(jsonschema.Prop("other", jsonschema.Integer())),
+		},
+		{
+			name:     "different type",
+			defaults: map[string]any{"foo": "bar"},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", jsonschema.Integer())),
+			errors:   []string{`default value bar of field "foo" does not match its type ["integer"]`},
+		},
+		{
+			name:     "integral number",
+			defaults: map[string]any{"foo": float64(2)},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", jsonschema.Integer(jsonschema.Default(2)))),
+		},
+		{
+			name:     "not in the enum",
+			defaults: map[string]any{"foo": "c"},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", &jsonschema.Schema{Type: jsonschema.SchemaType{"string"}, Enum: []any{"a", "b"}})),
+			errors:   []string{`default value c of field "foo" is not one of its enum values [a b]`},
+		},
+		{
+			name:     "same duration in another unit",
+			defaults: map[string]any{"foo": "1m0s"},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", jsonschema.String(jsonschema.Format("duration"), jsonschema.Default("60s")))),
+		},
+		{
+			name:     "different nested default",
+			defaults: map[string]any{"foo": map[string]any{"bar": int64(1)}},
+			schema:   jsonschema.Object(jsonschema.Prop("foo", jsonschema.Object(jsonschema.Prop("bar", jsonschema.Integer(jsonschema.Default(2)))))),
+			errors:   []string{`default value 1 of field "foo.bar" differs from its default 2 in the schema`},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var errs []string
+			for _, err := range CompareConfigDefaults(test.defaults, test.schema) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, test.errors, errs)
+		})
+	}
+}
+
+func TestConfigDefaultKeys(t *testing.T) {
+	structs := []APIstruct{
+		{
+			Name: "Config",
+			Fields: []APIstructField{
+				{Name: "Endpoint", Type: "string", Tag: "`mapstructure:\"endpoint\"`"},
+				{Name: "Client", Type: "ClientConfig", FullType: "*ClientConfig", Tag: "`mapstructure:\"client\"`"},
+				{Name: "", Type: "Common", Tag: "`mapstructure:\",squash\"`"},
+				{Name: "Untagged", Type: "string"},
+			},
+		},
+		{Name: "ClientConfig", Fields: []APIstructField{{Name: "MaxIdle", Type: "int", Tag: "`mapstructure:\"max_idle\"`"}}},
+		{Name: "Common", Fields: []APIstructField{{Name: "Name", Type: "string", Tag: "`mapstructure:\"name\"`"}}},
+	}
+	defaults := map[string]any{
+		"Endpoint": "localhost",
+		"Client":   map[string]any{"MaxIdle": int64(10)},
+		"Common":   map[string]any{"Name": "common"},
+		"Untagged": "value",
+	}
+	assert.Equal(t, map[string]any{
+		"endpoint": "localhost",
+		"client":   map[string]any{"max_idle": int64(10)},
+		"name":     "common",
+	}, ConfigDefaultKeys(defaults, structs[0], structs))
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pkg is a test package used by checkapi tests of deprecations.
+package pkg
+
+// OldFunc returns nothing.
+//
+// Deprecated: [v1.1.0] Use NewFunc instead.
+func OldFunc() {}
+
+// RecentFunc returns nothing.
+//
+// Deprecated: Use NewFunc instead. Deprecated since v1.4.0.
+func RecentFunc() {}
+
+// UnversionedFunc returns nothing.
+//
+// Deprecated: Use NewFunc instead.
+func UnversionedFunc() {}
+
+// NewFunc returns nothing.
+func NewFunc() {}
+
+// SomeStruct is a struct.
+type SomeStruct struct {
+	// OldField is a field.
+	//
+	// Deprecated: [v0.9.0] Use NewField instead.
+	OldField string
+	NewField string
+}
+
+// OldMethod returns nothing.
+//
+// Deprecated: [v1.2.0] Use NewFunc instead.
+func (SomeStruct) OldMethod() {}
+
+const (
+	// OldValue is a value.
+	//
+	// Deprecated: [v1.3.0] Use NewFunc instead.
+	OldValue = "old"
+)
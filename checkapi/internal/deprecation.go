@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// sincePattern matches the version a declaration was deprecated in, as in "Deprecated: [v0.110.0] Use Bar instead."
+// or "Deprecated: Use Bar instead. Deprecated since v0.110.0.".
+var sincePattern = regexp.MustCompile(`(?:[Ss]ince |\[)(v\d+\.\d+\.\d+)`)
+
+// DeprecationIssue describes a deprecated declaration that does not follow the deprecation lifecycle.
+type DeprecationIssue struct {
+	Message string
+	Pos     Position
+}
+
+// DeprecationSince returns the version a declaration was deprecated in, read from its deprecation notice.
+func DeprecationSince(notice string) (string, bool) {
+	m := sincePattern.FindStringSubmatch(notice)
+	if m == nil || !semver.IsValid(m[1]) {
+		return "", false
+	}
+	return m[1], true
+}
+
+// CheckDeprecations reports the exported declarations of the API whose deprecation notice does not say
+// which version they were deprecated in, and, if the version of the module set of the module is known,
+// the ones deprecated more than maxMinorReleases minor releases ago, which must be removed.
+func CheckDeprecations(api API, maxMinorReleases int, version string) []DeprecationIssue {
+	var issues []DeprecationIssue
+	for _, d := range api.Deprecations {
+		if d.Internal {
+			continue
+		}
+		since, ok := DeprecationSince(d.Notice)
+		if !ok {
+			issues = append(issues, DeprecationIssue{
+				Message: fmt.Sprintf("%s is deprecated without the version it was deprecated in, add \"Deprecated since vX.Y.Z\" to its notice", d.Name),
+				Pos:     d.Pos,
+			})
+			continue
+		}
+		if version == "" || maxMinorReleases <= 0 {
+			continue
+		}
+		if releases, ok := minorReleasesSince(since, version); ok && releases > maxMinorReleases {
+			issues = append(issues, DeprecationIssue{
+				Message: fmt.Sprintf("%s was deprecated in %s, more than %d minor releases before %s, and must be removed", d.Name, since, maxMinorReleases, version),
+				Pos:     d.Pos,
+			})
+		}
+	}
+	return issues
+}
+
+// minorReleasesSince returns the number of minor releases between two versions.
+// A previous major version is older than any number of minor releases.
+// It returns false if since is newer than version.
+func minorReleasesSince(since string, version string) (int, bool) {
+	if semver.Compare(since, version) > 0 {
+		return 0, false
+	}
+	if semver.Major(since) != semver.Major(version) {
+		return math.MaxInt, true
+	}
+	return minor(version) - minor(since), true
+}
+
+// minor returns the minor number of a valid semantic version.
+func minor(v string) int {
+	_, rest, _ := strings.Cut(semver.MajorMinor(v), ".")
+	n, _ := strconv.Atoi(rest)
+	return n
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationSince(t *testing.T) {
+	for _, tt := range []struct {
+		notice string
+		since  string
+	}{
+		{notice: "Deprecated: [v0.110.0] Use Bar instead.", since: "v0.110.0"},
+		{notice: "Deprecated: Use Bar instead. Deprecated since v1.2.3.", since: "v1.2.3"},
+		{notice: "Deprecated: since v1.2.3, use Bar instead.", since: "v1.2.3"},
+		{notice: "Deprecated: Use Bar instead."},
+		{notice: "Deprecated: [v1.2] Use Bar instead."},
+	} {
+		t.Run(tt.notice, func(t *testing.T) {
+			since, ok := DeprecationSince(tt.notice)
+			require.Equal(t, tt.since != "", ok)
+			require.Equal(t, tt.since, since)
+		})
+	}
+}
+
+func TestCheckDeprecations(t *testing.T) {
+	api := API{Deprecations: []Deprecation{
+		{Name: "Missing", Notice: "Deprecated: Use Bar instead."},
+		{Name: "Internal", Notice: "Deprecated: Use Bar instead.", Internal: true},
+		{Name: "Recent", Notice: "Deprecated: [v0.108.0] Use Bar instead."},
+		{Name: "Old", Notice: "Deprecated: [v0.107.0] Use Bar instead."},
+		{Name: "Future", Notice: "Deprecated: [v0.111.0] Use Bar instead."},
+		{Name: "PreviousMajor", Notice: "Deprecated: [v0.109.0] Use Bar instead."},
+	}}
+	messages := func(issues []DeprecationIssue) []string {
+		var result []string
+		for _, i := range issues {
+			result = append(result, i.Message)
+		}
+		return result
+	}
+
+	require.Equal(t, []string{
+		`Missing is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice`,
+	}, messages(CheckDeprecations(api, 2, "")))
+	require.Equal(t, []string{
+		`Missing is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice`,
+	}, messages(CheckDeprecations(api, 0, "v0.110.0")))
+	require.Equal(t, []string{
+		`Missing is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice`,
+		"Old was deprecated in v0.107.0, more than 2 minor releases before v0.110.0, and must be removed",
+	}, messages(CheckDeprecations(api, 2, "v0.110.0")))
+	require.Equal(t, []string{
+		`Missing is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice`,
+		"Recent was deprecated in v0.108.0, more than 2 minor releases before v1.0.0, and must be removed",
+		"Old was deprecated in v0.107.0, more than 2 minor releases before v1.0.0, and must be removed",
+		"Future was deprecated in v0.111.0, more than 2 minor releases before v1.0.0, and must be removed",
+		"PreviousMajor was deprecated in v0.109.0, more than 2 minor releases before v1.0.0, and must be removed",
+	}, messages(CheckDeprecations(api, 2, "v1.0.0")))
+}
@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Rule IDs of the checks, matching their configuration keys.
+const (
+	RuleAllowedFunctions     = "allowed_functions"
+	RuleAllowedTypes         = "allowed_types"
+	RuleUnkeyedLiteral       = "unkeyed_literal_initialization"
+	RuleComponentAPI         = "component_api"
+	RuleJSONSchema           = "json_schema"
+	RuleEmbeddedConfigFields = "embedded_config_fields"
+	RuleBreakingChanges      = "breaking_changes"
+	RuleDeprecations         = "deprecations"
+	RuleExportedVariables    = "exported_variables"
+	RuleUnexportedReturns    = "unexported_return_types"
+	RuleUnexportedParameters = "unexported_parameter_types"
+	RulePlatformSpecificAPI  = "platform_specific_api"
+	RuleConfigConventions    = "config_conventions"
+	RuleCrossModuleImports   = "cross_module_internal_imports"
+)
+
+const (
+	// SeverityError fails the check.
+	SeverityError = "error"
+	// SeverityWarning is reported without failing the check.
+	SeverityWarning = "warning"
+)
+
+// Output formats of diagnostics.
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatSARIF  = "sarif"
+	FormatGitHub = "github"
+)
+
+// Position is the location of a declaration in the source.
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// String returns the position as file:line:column, omitting the parts that are not known.
+func (p Position) String() string {
+	switch {
+	case p.File == "":
+		return ""
+	case p.Line == 0:
+		return p.File
+	case p.Column == 0:
+		return fmt.Sprintf("%s:%d", p.File, p.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	}
+}
+
+// Diagnostic is a violation of a rule found in a module.
+type Diagnostic struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Module   string `json:"module"`
+	Position
+	Message string `json:"message"`
+}
+
+// Error returns the message of the diagnostic prefixed with its module.
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("[%s] %s", d.Module, d.Message)
+}
+
+// DiagnosticsError joins the diagnostics with an error severity, or returns nil if there are none.
+func DiagnosticsError(diags []Diagnostic) error {
+	var errs []error
+	for _, d := range diags {
+		if d.Severity != SeverityWarning {
+			errs = append(errs, d)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteDiagnostics writes the diagnostics in the given format.
+func WriteDiagnostics(w io.Writer, format string, diags []Diagnostic) error {
+	switch format {
+	case FormatText:
+		for _, d := range diags {
+			if _, err := fmt.Fprintln(w, textLine(d)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		if diags == nil {
+			diags = []Diagnostic{}
+		}
+		return writeJSON(w, diags)
+	case FormatSARIF:
+		return writeJSON(w, sarifLog(diags))
+	case FormatGitHub:
+		for _, d := range diags {
+			if _, err := fmt.Fprintln(w, githubAnnotation(d)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of %s, %s, %s or %s", format, FormatText, FormatJSON, FormatSARIF, FormatGitHub)
+	}
+}
+
+func textLine(d Diagnostic) string {
+	var sb strings.Builder
+	if pos := d.Position.String(); pos != "" {
+		sb.WriteString(pos + ": ")
+	}
+	if d.Severity == SeverityWarning {
+		sb.WriteString("warning: ")
+	}
+	sb.WriteString(fmt.Sprintf("%s (%s)", d.Error(), d.Rule))
+	return sb.String()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// githubAnnotation formats a diagnostic as a GitHub Actions workflow command.
+// See https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+func githubAnnotation(d Diagnostic) string {
+	command := "error"
+	if d.Severity == SeverityWarning {
+		command = "warning"
+	}
+	var props []string
+	if d.File != "" {
+		props = append(props, "file="+escapeGitHubProperty(d.File))
+		if d.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", d.Line))
+		}
+		if d.Column > 0 {
+			props = append(props, fmt.Sprintf("col=%d", d.Column))
+		}
+	}
+	props = append(props, "title="+escapeGitHubProperty("checkapi "+d.Rule))
+	return fmt.Sprintf("::%s %s::%s", command, strings.Join(props, ","), escapeGitHubData(d.Error()))
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+type sarifReport struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func sarifLog(diags []Diagnostic) sarifReport {
+	var ruleIDs []string
+	results := make([]sarifResult, 0, len(diags))
+	for _, d := range diags {
+		if !slices.Contains(ruleIDs, d.Rule) {
+			ruleIDs = append(ruleIDs, d.Rule)
+		}
+		result := sarifResult{
+			RuleID:  d.Rule,
+			Level:   d.Severity,
+			Message: sarifMessage{Text: d.Error()},
+		}
+		if d.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: strings.ReplaceAll(d.File, "\\", "/")},
+			}}
+			if d.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+	sort.Strings(ruleIDs)
+	rules := make([]sarifRule, len(ruleIDs))
+	for i, id := range ruleIDs {
+		rules[i] = sarifRule{ID: id}
+	}
+	return sarifReport{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "checkapi",
+				InformationURI: "https://github.com/open-telemetry/opentelemetry-go-build-tools/tree/main/checkapi",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+// NewDiagnostic returns a diagnostic with an error severity.
+func NewDiagnostic(rule string, folder string, pos Position, format string, args ...any) Diagnostic {
+	return Diagnostic{
+		Rule:     rule,
+		Severity: SeverityError,
+		Module:   folder,
+		Position: pos,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDiagnostics = []Diagnostic{
+	{
+		Rule:     RuleUnkeyedLiteral,
+		Severity: SeverityError,
+		Module:   "receiver/foo",
+		Position: Position{File: "receiver/foo/config.go", Line: 12, Column: 6},
+		Message:  `struct "Config" does not prevent unkeyed literal initialization`,
+	},
+	{
+		Rule:     RuleBreakingChanges,
+		Severity: SeverityWarning,
+		Module:   "receiver/foo",
+		Message:  `breaking change: function "NewFoo" removed`,
+	},
+}
+
+func TestDiagnosticsError(t *testing.T) {
+	require.NoError(t, DiagnosticsError(nil))
+	require.NoError(t, DiagnosticsError(testDiagnostics[1:]))
+	require.EqualError(t, DiagnosticsError(testDiagnostics), `[receiver/foo] struct "Config" does not prevent unkeyed literal initialization`)
+}
+
+func TestWriteDiagnosticsText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&buf, FormatText, testDiagnostics))
+	assert.Equal(t, `receiver/foo/config.go:12:6: [receiver/foo] struct "Config" does not prevent unkeyed literal initialization (unkeyed_literal_initialization)
+warning: [receiver/foo] breaking change: function "NewFoo" removed (breaking_changes)
+`, buf.String())
+}
+
+func TestWriteDiagnosticsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&buf, FormatJSON, nil))
+	assert.JSONEq(t, `[]`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteDiagnostics(&buf, FormatJSON, testDiagnostics))
+	var read []Diagnostic
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &read))
+	assert.Equal(t, testDiagnostics, read)
+}
+
+func TestWriteDiagnosticsSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&buf, FormatSARIF, testDiagnostics))
+	var report sarifReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+	assert.Equal(t, []sarifRule{{ID: RuleBreakingChanges}, {ID: RuleUnkeyedLiteral}}, report.Runs[0].Tool.Driver.Rules)
+	assert.Equal(t, []sarifResult{
+		{
+			RuleID:  RuleUnkeyedLiteral,
+			Level:   "error",
+			Message: sarifMessage{Text: `[receiver/foo] struct "Config" does not prevent unkeyed literal initialization`},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "receiver/foo/config.go"},
+				Region:           &sarifRegion{StartLine: 12, StartColumn: 6},
+			}}},
+		},
+		{
+			RuleID:  RuleBreakingChanges,
+			Level:   "warning",
+			Message: sarifMessage{Text: `[receiver/foo] breaking change: function "NewFoo" removed`},
+		},
+	}, report.Runs[0].Results)
+}
+
+func TestWriteDiagnosticsGitHub(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&buf, FormatGitHub, append(testDiagnostics, Diagnostic{
+		Rule:     RuleJSONSchema,
+		Severity: SeverityError,
+		Module:   "receiver/foo",
+		Position: Position{File: "receiver/foo/metadata.yaml"},
+		Message:  "new JSON schema: type: object\n",
+	})))
+	assert.Equal(t, `::error file=receiver/foo/config.go,line=12,col=6,title=checkapi unkeyed_literal_initialization::[receiver/foo] struct "Config" does not prevent unkeyed literal initialization
+::warning title=checkapi breaking_changes::[receiver/foo] breaking change: function "NewFoo" removed
+::error file=receiver/foo/metadata.yaml,title=checkapi json_schema::[receiver/foo] new JSON schema: type: object%0A
+`, buf.String())
+}
+
+func TestWriteDiagnosticsUnknownFormat(t *testing.T) {
+	require.EqualError(t, WriteDiagnostics(&bytes.Buffer{}, "xml", testDiagnostics), `unknown output format "xml", must be one of text, json, sarif or github`)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// typeNameTokenPattern matches the type names, qualified or not, of a type in its canonical form.
+var typeNameTokenPattern = regexp.MustCompile(qualifiedNamePattern.String() + `|[\p{L}_][\p{L}\p{N}_]*`)
+
+// MutableValues returns the exported variables of the API outside of internal packages,
+// except the ones whose name or type matches one of the allowed patterns.
+func MutableValues(api API, cfg ExportedVariables) ([]APIvalue, error) {
+	var result []APIvalue
+VALUE:
+	for _, v := range api.Values {
+		if v.Kind != ValueKindVar || v.Internal || !ast.IsExported(shortName(v.Name)) {
+			continue
+		}
+		for _, pattern := range cfg.AllowedNames {
+			ok, err := matchPattern(pattern, v.Name)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue VALUE
+			}
+		}
+		for _, pattern := range cfg.AllowedTypes {
+			ok, err := matchType(pattern, v.Type)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue VALUE
+			}
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// UnexportedTypes returns the unexported named types used in a list of types in their canonical form,
+// as "config" in "*config" or "map[string][]pkg.entry", in order of appearance.
+// Predeclared types, the type parameters of the declaration and the fields and methods of anonymous structs
+// and interfaces are not reported.
+func UnexportedTypes(typeList []string, typeParams []string) []string {
+	var result []string
+	for _, t := range typeList {
+		for _, name := range typeNameTokenPattern.FindAllString(stripAnonymousTypes(t), -1) {
+			if !strings.Contains(name, ".") && (token.IsKeyword(name) || types.Universe.Lookup(name) != nil || slices.Contains(typeParams, name)) {
+				continue
+			}
+			if !ast.IsExported(shortName(name)) && !slices.Contains(result, name) {
+				result = append(result, name)
+			}
+		}
+	}
+	return result
+}
+
+// stripAnonymousTypes removes the bodies of the anonymous structs and interfaces of a type,
+// which hold field and method names rather than types.
+func stripAnonymousTypes(t string) string {
+	var b strings.Builder
+	depth := 0
+	for _, c := range t {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case depth == 0:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutableValues(t *testing.T) {
+	api := API{Values: []APIvalue{
+		{Name: "Version", Kind: ValueKindConst, Type: "untyped string"},
+		{Name: "ErrNotFound", Kind: ValueKindVar, Type: "error"},
+		{Name: "Registry", Kind: ValueKindVar, Type: "map[string]Factory"},
+		{Name: "DefaultConfig", Kind: ValueKindVar, Type: "Config"},
+		{Name: "pkg.Global", Kind: ValueKindVar, Type: "int"},
+		{Name: "InternalGlobal", Kind: ValueKindVar, Type: "int", Internal: true},
+	}}
+	values, err := MutableValues(api, ExportedVariables{AllowedTypes: []string{"error"}, AllowedNames: []string{"Default*"}})
+	require.NoError(t, err)
+	assert.Equal(t, []APIvalue{
+		{Name: "Registry", Kind: ValueKindVar, Type: "map[string]Factory"},
+		{Name: "pkg.Global", Kind: ValueKindVar, Type: "int"},
+	}, values)
+
+	_, err = MutableValues(api, ExportedVariables{AllowedNames: []string{"/(/"}})
+	require.Error(t, err)
+}
+
+func TestUnexportedTypes(t *testing.T) {
+	for _, tt := range []struct {
+		types      []string
+		typeParams []string
+		expected   []string
+	}{
+		{types: []string{"string", "error", "any", "*Config"}},
+		{types: []string{"*config", "[]config", "map[string]*entry"}, expected: []string{"config", "entry"}},
+		{types: []string{"pkg.settings", "pkg.Settings"}, expected: []string{"pkg.settings"}},
+		{types: []string{"func(context.Context) (*result,error)", "chan<- event"}, expected: []string{"result", "event"}},
+		{types: []string{"Option[state]", "struct{value int}", "interface{do()}"}, expected: []string{"state"}},
+		{types: []string{"options..."}, expected: []string{"options"}},
+		{types: []string{"T", "[]V", "map[K]config"}, typeParams: []string{"T", "K", "V"}, expected: []string{"config"}},
+	} {
+		assert.Equal(t, tt.expected, UnexportedTypes(tt.types, tt.typeParams), "%q", tt.types)
+	}
+}
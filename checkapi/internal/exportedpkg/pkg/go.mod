@@ -0,0 +1,3 @@
+module foo/bar/exportedpkg/pkg
+
+go 1.25.0
@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pkg is a test package used by checkapi tests of exported variables and unexported types.
+package pkg
+
+import "errors"
+
+// Version is a constant.
+const Version = "v1.0.0"
+
+// ErrNotFound is a sentinel error.
+var ErrNotFound = errors.New("not found")
+
+// Registry is a mutable global.
+var Registry = map[string]string{}
+
+type settings struct{}
+
+// NewSettings returns an unexported type.
+func NewSettings() *settings {
+	return &settings{}
+}
+
+// Apply takes an unexported type.
+func Apply(_ settings) error {
+	return nil
+}
+
+// Map takes and returns its type parameter, which is not an unexported type.
+func Map[T any](t T) T {
+	return t
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// InternalImport is an import of an internal package of another module.
+type InternalImport struct {
+	// Path is the import path of the internal package.
+	Path string
+	// Module is the path of the module the internal package belongs to.
+	Module string
+	Pos    Position
+}
+
+// ModulePaths returns the paths of the modules declared by the go.mod files under folder, sorted.
+func ModulePaths(folder string) ([]string, error) {
+	folders, err := ModuleFolders(folder)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(folders)), nil
+}
+
+// RepositoryModulePaths returns the paths of the modules of the git repository holding folder, sorted:
+// the modules under the closest folder with a .git entry, or under folder if it is not in a repository.
+// The internal packages of these modules are the ones checked by the cross_module_internal_imports rule.
+func RepositoryModulePaths(folder string) ([]string, error) {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return nil, err
+	}
+	root := absFolder
+	for dir := absFolder; ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			root = dir
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ModulePaths(root)
+}
+
+// ModuleFolders maps the paths of the modules declared by the go.mod files under folder to their folder,
+// relative to folder.
+func ModuleFolders(folder string) (map[string]string, error) {
+	folders := map[string]string{}
+	err := filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		modulePath, err := ReadModulePath(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(folder, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		folders[modulePath] = rel
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// CheckInternalImports returns the imports of internal packages that belong to another module than modulePath,
+// except for the allowed import paths, which may be patterns. A package belongs to the module of modulePaths
+// with the longest path prefixing its import path. Imports of packages outside of these modules are ignored.
+func CheckInternalImports(api API, modulePath string, modulePaths []string, allowed []string) ([]InternalImport, error) {
+	var result []InternalImport
+IMPORTS:
+	for _, imp := range api.Imports {
+		if !slices.Contains(strings.Split(imp.Path, "/"), "internal") {
+			continue
+		}
+		module := owningModule(imp.Path, modulePaths)
+		if module == "" || module == modulePath {
+			continue
+		}
+		for _, pattern := range allowed {
+			ok, err := matchPattern(pattern, imp.Path)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue IMPORTS
+			}
+		}
+		result = append(result, InternalImport{Path: imp.Path, Module: module, Pos: imp.Pos})
+	}
+	return result, nil
+}
+
+// owningModule returns the module with the longest path prefixing a package path, or an empty string if there is none.
+func owningModule(pkgPath string, modulePaths []string) string {
+	var owner string
+	for _, m := range modulePaths {
+		if (pkgPath == m || strings.HasPrefix(pkgPath, m+"/")) && len(m) > len(owner) {
+			owner = m
+		}
+	}
+	return owner
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModulePaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                 "module example.com/repo\n",
+		"receiver/go.mod":        "module example.com/repo/receiver\n",
+		"receiver/sub/go.mod":    "module example.com/repo/receiver/sub\n",
+		"receiver/internal/a.go": "package internal\n",
+	})
+	paths, err := ModulePaths(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/repo", "example.com/repo/receiver", "example.com/repo/receiver/sub"}, paths)
+
+	// without a repository, only the modules under the folder are known.
+	paths, err = RepositoryModulePaths(filepath.Join(dir, "receiver"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/repo/receiver", "example.com/repo/receiver/sub"}, paths)
+
+	writeFiles(t, dir, map[string]string{".git/HEAD": "ref: refs/heads/main\n"})
+	paths, err = RepositoryModulePaths(filepath.Join(dir, "receiver"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com/repo", "example.com/repo/receiver", "example.com/repo/receiver/sub"}, paths)
+}
+
+func TestCheckInternalImports(t *testing.T) {
+	modulePaths := []string{"example.com/repo", "example.com/repo/receiver", "example.com/repo/receiver/sub"}
+	api := API{Imports: []APIimport{
+		{Path: "fmt"},
+		{Path: "example.com/repo/receiver/internal/own"},
+		{Path: "example.com/repo/internal/shared", Pos: Position{File: "a.go", Line: 3}},
+		{Path: "example.com/repo/internal/allowed/sub"},
+		{Path: "example.com/repo/receiver/sub/internal/nested", Pos: Position{File: "b.go", Line: 4}},
+		{Path: "example.com/other/internal/foo"},
+	}}
+	imports, err := CheckInternalImports(api, "example.com/repo/receiver", modulePaths, []string{"example.com/repo/internal/allowed/*"})
+	require.NoError(t, err)
+	assert.Equal(t, []InternalImport{
+		{Path: "example.com/repo/internal/shared", Module: "example.com/repo", Pos: Position{File: "a.go", Line: 3}},
+		{Path: "example.com/repo/receiver/sub/internal/nested", Module: "example.com/repo/receiver/sub", Pos: Position{File: "b.go", Line: 4}},
+	}, imports)
+
+	_, err = CheckInternalImports(api, "example.com/repo/receiver", modulePaths, []string{"/[/"})
+	require.Error(t, err)
+}
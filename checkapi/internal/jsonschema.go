@@ -4,17 +4,46 @@
 package internal
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
 	"slices"
 	"strings"
 
 	"github.com/kaptinlin/jsonschema"
 )
 
-// DeriveSchema interprets the config struct to return a valid JSON schema
-func DeriveSchema(cfgStruct APIstruct, structs []APIstruct, typeMappings map[string]string) (*jsonschema.Schema, error) {
-	return createObject(parseObject(cfgStruct, structs, typeMappings)), nil
+// DeriveSchema interprets the config struct to return a valid JSON schema.
+// Fields are named after their mapstructure tag, and documented with their doc comment.
+// Pointer fields and fields mapped to a type parameter are nullable, unless they are tagged omitempty,
+// and time.Duration fields are duration-formatted strings.
+// Slices are arrays of the schema of their elements, and instantiated generic structs are derived with their type arguments.
+// The values of the constants declared with a named string type are the enum of the fields of that type.
+func DeriveSchema(cfgStruct APIstruct, api API, typeMappings map[string]string) (*jsonschema.Schema, error) {
+	d := schemaDeriver{structs: api.Structs, enums: api.Enums, typeMappings: typeMappings}
+	return createObject(d.parseObject(cfgStruct, nil)), nil
+}
+
+// SchemaToYAML returns the schema as a value that can be marshaled as YAML, as it appears in metadata.yaml files.
+func SchemaToYAML(schema *jsonschema.Schema) (map[string]any, error) {
+	b, err := schema.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]any{}
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// schemaDeriver derives the JSON schema of config structs.
+type schemaDeriver struct {
+	structs      []APIstruct
+	enums        map[string][]string
+	typeMappings map[string]string
 }
 
 func matchingStruct(structRef string, structs []APIstruct) APIstruct {
@@ -29,32 +58,75 @@ func matchingStruct(structRef string, structs []APIstruct) APIstruct {
 	return selected
 }
 
-func parseObject(s APIstruct, structs []APIstruct, typeMappings map[string]string) []any {
+// parseObject returns the properties of a struct, the references of the squashed fields mapped to a schema,
+// and the additionalProperties keyword of its remain field.
+// The type parameters of a generic struct are replaced by the type arguments they are bound to.
+func (d schemaDeriver) parseObject(s APIstruct, bindings map[string]string) []any {
 	var fields []any
-	for _, f := range s.Fields {
-		if f.Tag == "" {
+	for i, f := range s.Fields {
+		name, options, ok := ParseTag(f.Tag)
+		if !ok || name == "-" {
 			continue
 		}
-		tagValue := readTag(f.Tag)
-		if tagValue == ",squash" {
-			if found, ok := typeMappings[f.Type]; ok {
-				fields = append(fields, jsonschema.Ref(found))
-			} else {
-				selected := matchingStruct(f.Type, structs)
-				fields = append(fields, parseObject(selected, structs, typeMappings)...)
-			}
-		} else {
-			if found, ok := typeMappings[f.Type]; ok {
-				fields = append(fields, jsonschema.Prop(tagValue, jsonschema.Ref(found)))
+		// fields without a name in their tag are keyed by their name, as in mapstructureKeys.
+		if name == "" {
+			name = f.Name
+		}
+		// map and generic fields are recorded once for each type they hold, only the last one is kept.
+		if f.Name != "" && i+1 < len(s.Fields) && s.Fields[i+1].Name == f.Name {
+			continue
+		}
+		fieldType := substituteTypeParams(fullType(f), bindings)
+		switch {
+		case slices.Contains(options, "squash"):
+			fields = append(fields, d.squash(strings.TrimPrefix(fieldType, "*"))...)
+		case slices.Contains(options, "remain"):
+			_, value, _ := splitMapType(fieldType)
+			if value == "any" || value == "interface{}" {
+				fields = append(fields, jsonschema.AdditionalProps(true))
 			} else {
-				fields = append(fields, jsonschema.Prop(tagValue, fieldTypeToJSONType(f.Type, structs, typeMappings)))
+				fields = append(fields, jsonschema.AdditionalPropsSchema(d.fieldTypeToJSONType(value)))
 			}
+		case name != "":
+			fields = append(fields, jsonschema.Prop(name, d.fieldSchema(f, fieldType, options)))
 		}
 	}
 	return fields
 }
 
-func fieldTypeToJSONType(fieldType string, structs []APIstruct, typeMappings map[string]string) *jsonschema.Schema {
+// squash returns the properties of an embedded struct, or a reference to its schema if it is mapped to one.
+func (d schemaDeriver) squash(fieldType string) []any {
+	if target, isType, ok := d.mapping(fieldType); ok {
+		if isType {
+			return d.squash(target)
+		}
+		return []any{jsonschema.Ref(target)}
+	}
+	name, args := splitTypeArgs(fieldType)
+	s := matchingStruct(name, d.structs)
+	return d.parseObject(s, bindTypeParams(s.TypeParams, args))
+}
+
+// fieldSchema returns the schema of a field, documented with its doc comment.
+func (d schemaDeriver) fieldSchema(f APIstructField, fieldType string, options []string) *jsonschema.Schema {
+	schema := d.fieldTypeToJSONType(fieldType)
+	_, optional, _ := d.mapping(fieldType)
+	if (strings.HasPrefix(fieldType, "*") || optional) && !slices.Contains(options, "omitempty") && len(schema.Type) > 0 {
+		schema.Type = append(slices.Clip(schema.Type), "null")
+	}
+	if f.Doc != "" {
+		jsonschema.Description(f.Doc)(schema)
+	}
+	return schema
+}
+
+func (d schemaDeriver) fieldTypeToJSONType(fieldType string) *jsonschema.Schema {
+	if target, isType, ok := d.mapping(fieldType); ok {
+		if isType {
+			return d.fieldTypeToJSONType(target)
+		}
+		return jsonschema.Ref(target)
+	}
 	switch fieldType {
 	case "string":
 		return jsonschema.String()
@@ -62,12 +134,85 @@ func fieldTypeToJSONType(fieldType string, structs []APIstruct, typeMappings map
 		return jsonschema.Boolean()
 	case "int":
 		return jsonschema.Integer()
-	case "[]string":
-		return jsonschema.Array(jsonschema.Items(jsonschema.String()))
-	default:
-		selected := matchingStruct(fieldType, structs)
-		return createObject(parseObject(selected, structs, typeMappings))
+	case "time.Duration":
+		return jsonschema.Duration()
+	}
+	if values, ok := d.enums[fieldType]; ok {
+		schema := jsonschema.String()
+		for _, v := range values {
+			schema.Enum = append(schema.Enum, v)
+		}
+		return schema
+	}
+	// map fields have the schema of their values.
+	if _, value, ok := splitMapType(fieldType); ok {
+		return d.fieldTypeToJSONType(value)
+	}
+	switch {
+	case strings.HasPrefix(fieldType, "*"):
+		return d.fieldTypeToJSONType(fieldType[1:])
+	case strings.HasPrefix(fieldType, "[]"):
+		return jsonschema.Array(jsonschema.Items(d.fieldTypeToJSONType(fieldType[2:])))
+	}
+	name, args := splitTypeArgs(fieldType)
+	s := matchingStruct(name, d.structs)
+	return createObject(d.parseObject(s, bindTypeParams(s.TypeParams, args)))
+}
+
+// mapping returns the target of the type mapping matching a type. Type mappings may use type parameters,
+// written as $ followed by a name, to match any type argument, as in "configoptional.Optional[$T]".
+// If the target is a single type parameter, the bound type argument is returned and isType is true:
+// its schema is derived as the schema of the mapped type, and fields of the mapped type are optional. Otherwise, the target is a reference to a schema,
+// in which type parameters are replaced by their type argument.
+func (d schemaDeriver) mapping(fieldType string) (target string, isType bool, ok bool) {
+	if found, ok := d.typeMappings[fieldType]; ok {
+		return found, false, true
+	}
+	if found, ok := d.typeMappings[ShortTypeString(fieldType)]; ok {
+		return found, false, true
+	}
+	patterns := make([]string, 0, len(d.typeMappings))
+	for pattern := range d.typeMappings {
+		if strings.Contains(pattern, "$") {
+			patterns = append(patterns, pattern)
+		}
+	}
+	// iterate in a stable order, as several patterns may match the same type.
+	slices.Sort(patterns)
+	for _, pattern := range patterns {
+		bindings, ok := matchTypePattern(pattern, fieldType)
+		if !ok {
+			continue
+		}
+		target := d.typeMappings[pattern]
+		if bound, ok := bindings[strings.TrimPrefix(target, "$")]; ok && strings.HasPrefix(target, "$") {
+			return bound, true, true
+		}
+		return typeParamPattern.ReplaceAllStringFunc(target, func(p string) string {
+			return bindings[p[1:]]
+		}), false, true
+	}
+	return "", false, false
+}
+
+// fullType returns the type of a field as declared.
+func fullType(f APIstructField) string {
+	if f.FullType != "" {
+		return f.FullType
+	}
+	return f.Type
+}
+
+// bindTypeParams maps the type parameters of a generic struct to the type arguments of one of its instantiations.
+func bindTypeParams(typeParams []string, args []string) map[string]string {
+	if len(typeParams) != len(args) {
+		return nil
+	}
+	bindings := make(map[string]string, len(typeParams))
+	for i, p := range typeParams {
+		bindings[p] = args[i]
 	}
+	return bindings
 }
 
 func createObject(fields []any) *jsonschema.Schema {
@@ -82,45 +227,57 @@ func createObject(fields []any) *jsonschema.Schema {
 	return obj
 }
 
-func readTag(tag string) string {
-	cut, _ := strings.CutSuffix(tag, "\"`")
-	cut, _ = strings.CutPrefix(cut, "`mapstructure:\"")
-	cut, _ = strings.CutSuffix(cut, ",omitempty")
-	return cut
+// ParseTag returns the name and the options of the mapstructure tag of a field, if it has one.
+func ParseTag(tag string) (string, []string, bool) {
+	value, ok := reflect.StructTag(strings.Trim(tag, "`")).Lookup("mapstructure")
+	if !ok {
+		return "", nil, false
+	}
+	name, options, _ := strings.Cut(value, ",")
+	return name, strings.Split(options, ","), true
 }
 
 // CompareJSONSchema compares the presence of fields and their types.
-func CompareJSONSchema(folder string, before *jsonschema.Schema, after *jsonschema.Schema) error {
+func CompareJSONSchema(before *jsonschema.Schema, after *jsonschema.Schema) error {
 	if before.Properties == nil && after.Properties == nil {
 		return nil
 	}
 	if before.Properties == nil || after.Properties == nil || len(*before.Properties) != len(*after.Properties) {
-		return fmt.Errorf("[%s] number of fields differ", folder)
+		return errors.New("number of fields differ")
 	}
-	return compareProperties(folder, before, after)
+	return compareProperties(before, after)
 }
 
-func compareProperties(folder string, before *jsonschema.Schema, after *jsonschema.Schema) error {
+func compareProperties(before *jsonschema.Schema, after *jsonschema.Schema) error {
 	var errs []error
 	if before.Properties != nil {
-		for name, bs := range *before.Properties {
+		// compare in a stable order, so that the same changes are always reported the same way.
+		for _, name := range slices.Sorted(maps.Keys(*before.Properties)) {
+			bs := (*before.Properties)[name]
 			as, ok := (*after.Properties)[name]
 			if !ok {
-				errs = append(errs, fmt.Errorf("[%s] field %q is missing", folder, name))
+				errs = append(errs, fmt.Errorf("field %q is missing", name))
 			} else {
 				if !slices.Equal(bs.Type, as.Type) {
-					errs = append(errs, fmt.Errorf("[%s] field %q type changed", folder, name))
+					errs = append(errs, fmt.Errorf("field %q type changed", name))
 				}
 				if bs.Ref != as.Ref {
-					errs = append(errs, fmt.Errorf("[%s] field %q ref changed", folder, name))
+					errs = append(errs, fmt.Errorf("field %q ref changed", name))
+				}
+				if stringValue(bs.Format) != stringValue(as.Format) {
+					errs = append(errs, fmt.Errorf("field %q format changed", name))
+				}
+				if !slices.Equal(enumValues(bs), enumValues(as)) {
+					errs = append(errs, fmt.Errorf("field %q enum changed", name))
 				}
 				if bs.Properties != nil {
-					for subName, subBs := range *bs.Properties {
+					for _, subName := range slices.Sorted(maps.Keys(*bs.Properties)) {
+						subBs := (*bs.Properties)[subName]
 						subAs, ok := (*as.Properties)[subName]
 						if !ok {
-							errs = append(errs, fmt.Errorf("[%s] property %q is missing", folder, subName))
+							errs = append(errs, fmt.Errorf("property %q is missing", subName))
 						} else {
-							errs = append(errs, compareProperties(folder, subBs, subAs))
+							errs = append(errs, compareProperties(subBs, subAs))
 						}
 					}
 				}
@@ -128,13 +285,30 @@ func compareProperties(folder string, before *jsonschema.Schema, after *jsonsche
 		}
 	}
 	if len(before.AllOf) != len(after.AllOf) {
-		errs = append(errs, fmt.Errorf("[%s] references length do not match %d %d", folder, len(before.AllOf), len(after.AllOf)))
+		errs = append(errs, fmt.Errorf("references length do not match %d %d", len(before.AllOf), len(after.AllOf)))
 	}
 	for i, b := range before.AllOf {
 		a := after.AllOf[i]
 		if a.Ref != b.Ref {
-			errs = append(errs, fmt.Errorf("[%s] references do not match %q %q", folder, a.Ref, b.Ref))
+			errs = append(errs, fmt.Errorf("references do not match %q %q", a.Ref, b.Ref))
 		}
 	}
 	return errors.Join(errs...)
 }
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// enumValues returns the sorted enum values of a schema.
+func enumValues(s *jsonschema.Schema) []string {
+	values := make([]string, len(s.Enum))
+	for i, v := range s.Enum {
+		values[i] = fmt.Sprint(v)
+	}
+	slices.Sort(values)
+	return values
+}
@@ -34,9 +34,26 @@ func TestCompareSchemas(t *testing.T) {
 			after:  jsonschema.Object(jsonschema.Prop("foo", jsonschema.Boolean())),
 			error:  `field "foo" type changed`,
 		},
+		{
+			name:   "different format",
+			before: jsonschema.Object(jsonschema.Prop("foo", jsonschema.String())),
+			after:  jsonschema.Object(jsonschema.Prop("foo", jsonschema.Duration())),
+			error:  `field "foo" format changed`,
+		},
+		{
+			name:   "same enum in another order",
+			before: jsonschema.Object(jsonschema.Prop("foo", &jsonschema.Schema{Type: jsonschema.SchemaType{"string"}, Enum: []any{"b", "a"}})),
+			after:  jsonschema.Object(jsonschema.Prop("foo", &jsonschema.Schema{Type: jsonschema.SchemaType{"string"}, Enum: []any{"a", "b"}})),
+		},
+		{
+			name:   "different enum",
+			before: jsonschema.Object(jsonschema.Prop("foo", &jsonschema.Schema{Type: jsonschema.SchemaType{"string"}, Enum: []any{"a"}})),
+			after:  jsonschema.Object(jsonschema.Prop("foo", &jsonschema.Schema{Type: jsonschema.SchemaType{"string"}, Enum: []any{"a", "b"}})),
+			error:  `field "foo" enum changed`,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			err := CompareJSONSchema("", test.before, test.after)
+			err := CompareJSONSchema(test.before, test.after)
 			if test.error != "" {
 				assert.ErrorContains(t, err, test.error)
 			} else {
@@ -46,3 +63,91 @@ func TestCompareSchemas(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveSchema(t *testing.T) {
+	api := API{
+		Structs: []APIstruct{
+			{Name: "Config", Fields: []APIstructField{
+				{Name: "Endpoint", Type: "string", Tag: "`mapstructure:\"endpoint,omitempty\"`", Doc: "Endpoint is the address to listen on."},
+				{Name: "Timeout", Type: "time.Duration", Tag: "`mapstructure:\"timeout\"`"},
+				{Name: "Mode", Type: "Mode", Tag: "`mapstructure:\"mode\"`"},
+				{Name: "TLS", Type: "TLSConfig", FullType: "*TLSConfig", Tag: "`mapstructure:\"tls\"`"},
+				{Name: "Limit", Type: "int", FullType: "*int", Tag: "`mapstructure:\"limit,omitempty\"`"},
+				{Name: "Other", Type: "string", FullType: "map[string]any", Tag: "`mapstructure:\",remain\"`"},
+				{Name: "Other", Type: "any", FullType: "map[string]any", Tag: "`mapstructure:\",remain\"`"},
+				{Name: "Internal", Type: "string", Tag: "`json:\"internal\"`"},
+				{Name: "Retries", Type: "int", Tag: "`mapstructure:\",omitempty\"`"},
+				{Name: "Ignored", Type: "string", Tag: "`mapstructure:\"-\"`"},
+			}},
+			{Name: "TLSConfig", Fields: []APIstructField{
+				{Name: "Insecure", Type: "bool", Tag: "`mapstructure:\"insecure\"`"},
+			}},
+		},
+		Enums: map[string][]string{"Mode": {"push", "pull"}},
+	}
+	schema, err := DeriveSchema(api.Structs[0], api, nil)
+	assert.NoError(t, err)
+	raw, err := SchemaToYAML(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"type":                 "object",
+		"additionalProperties": true,
+		"properties": map[string]any{
+			"endpoint": map[string]any{"type": "string", "description": "Endpoint is the address to listen on."},
+			"timeout":  map[string]any{"type": "string", "format": "duration"},
+			"mode":     map[string]any{"type": "string", "enum": []any{"push", "pull"}},
+			"tls": map[string]any{
+				"type":       []any{"object", "null"},
+				"properties": map[string]any{"insecure": map[string]any{"type": "boolean"}},
+			},
+			"limit":   map[string]any{"type": "integer"},
+			"Retries": map[string]any{"type": "integer"},
+		},
+	}, raw)
+}
+
+func TestDeriveSchemaGenerics(t *testing.T) {
+	api := API{
+		Structs: []APIstruct{
+			{Name: "Config", Fields: []APIstructField{
+				{Name: "Server", Type: "ServerConfig", FullType: "configoptional.Optional[ServerConfig]", Tag: "`mapstructure:\"server\"`"},
+				{Name: "Server", Type: "configoptional.Optional", FullType: "configoptional.Optional[ServerConfig]", Tag: "`mapstructure:\"server\"`"},
+				{Name: "Pipelines", Type: "ServerConfig", FullType: "[]Pair[string,ServerConfig]", Tag: "`mapstructure:\"pipelines\"`"},
+				{Name: "Pipelines", Type: "Pair", FullType: "[]Pair[string,ServerConfig]", Tag: "`mapstructure:\"pipelines\"`"},
+				{Name: "Headers", Type: "string", FullType: "configopaque.MapList[string]", Tag: "`mapstructure:\"headers\"`"},
+			}},
+			{Name: "ServerConfig", Fields: []APIstructField{
+				{Name: "Endpoint", Type: "string", Tag: "`mapstructure:\"endpoint\"`"},
+			}},
+			{Name: "Pair", TypeParams: []string{"K", "V"}, Fields: []APIstructField{
+				{Name: "Key", Type: "K", Tag: "`mapstructure:\"key\"`"},
+				{Name: "Values", Type: "V", FullType: "[]V", Tag: "`mapstructure:\"values\"`"},
+			}},
+		},
+	}
+	schema, err := DeriveSchema(api.Structs[0], api, map[string]string{
+		"configoptional.Optional[$T]": "$T",
+		"configopaque.MapList[$T]":    "http://example.com/schemas/maplist.json#/$T",
+	})
+	assert.NoError(t, err)
+	raw, err := SchemaToYAML(schema)
+	assert.NoError(t, err)
+	endpoint := map[string]any{"type": "object", "properties": map[string]any{"endpoint": map[string]any{"type": "string"}}}
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"server": map[string]any{"type": []any{"object", "null"}, "properties": endpoint["properties"]},
+			"pipelines": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":    map[string]any{"type": "string"},
+						"values": map[string]any{"type": "array", "items": endpoint},
+					},
+				},
+			},
+			"headers": map[string]any{"$ref": "http://example.com/schemas/maplist.json#/string"},
+		},
+	}, raw)
+}
@@ -4,7 +4,9 @@
 package internal
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -20,6 +22,8 @@ type Status struct {
 type Metadata struct {
 	Status Status `yaml:"status"`
 	Config any    `yaml:"config"`
+	// CheckAPI overrides the checkapi configuration for this component.
+	CheckAPI *ModuleConfig `yaml:"checkapi"`
 }
 
 // ReadMetadata reads from the metadata.yaml file in the given folder.
@@ -37,3 +41,50 @@ func ReadMetadata(folder string) (Metadata, bool, error) {
 	}
 	return componentInfo, true, nil
 }
+
+// WriteMetadataConfig replaces the config section of the metadata.yaml file in the given folder,
+// or adds it if missing. The rest of the file and its comments are kept.
+func WriteMetadataConfig(folder string, config any) error {
+	path := filepath.Join(folder, "metadata.yaml")
+	b, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err = yaml.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a mapping", path)
+	}
+	value := &yaml.Node{}
+	if err = value.Encode(config); err != nil {
+		return err
+	}
+
+	root := doc.Content[0]
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "config" {
+			previous := root.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = previous.HeadComment, previous.LineComment, previous.FootComment
+			root.Content[i+1] = value
+			found = true
+			break
+		}
+	}
+	if !found {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "config"}, value)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err = enc.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleConfigFileName is the name of the file holding the configuration overrides of a module,
+// as an alternative to the checkapi section of its metadata.yaml file.
+const ModuleConfigFileName = ".checkapi.yaml"
+
+// Rules lists the IDs of the rules that can be enabled or disabled per module.
+var Rules = []string{
+	RuleAllowedFunctions,
+	RuleAllowedTypes,
+	RuleUnkeyedLiteral,
+	RuleComponentAPI,
+	RuleJSONSchema,
+	RuleEmbeddedConfigFields,
+	RuleBreakingChanges,
+	RuleDeprecations,
+	RuleExportedVariables,
+	RuleUnexportedReturns,
+	RuleUnexportedParameters,
+	RulePlatformSpecificAPI,
+	RuleConfigConventions,
+	RuleCrossModuleImports,
+}
+
+// ModuleConfig represents the overrides of the configuration for a single module.
+type ModuleConfig struct {
+	// Rules enables or disables rules by ID.
+	Rules map[string]bool `yaml:"rules"`
+	// IgnoredFunctions are added to the functions ignored by the global configuration.
+	IgnoredFunctions []string `yaml:"ignored_functions"`
+	// ExcludedFiles are added to the files excluded by the global configuration.
+	ExcludedFiles []string `yaml:"excluded_files"`
+	// UnkeyedLiteral overrides the settings of the unkeyed literal initialization check.
+	UnkeyedLiteral UnkeyedLiteralOverride `yaml:"unkeyed_literal_initialization"`
+}
+
+// UnkeyedLiteralOverride overrides the settings of the unkeyed literal initialization check that are set.
+type UnkeyedLiteralOverride struct {
+	Limit *int `yaml:"limit"`
+}
+
+// ReadModuleConfig returns the configuration overrides of the module in the given folder,
+// read from the checkapi section of its metadata or from its .checkapi.yaml file.
+// Setting overrides in both places is an error.
+func ReadModuleConfig(folder string, metadata Metadata) (ModuleConfig, error) {
+	path := filepath.Join(folder, ModuleConfigFileName)
+	b, err := os.ReadFile(path) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		if metadata.CheckAPI == nil {
+			return ModuleConfig{}, nil
+		}
+		return *metadata.CheckAPI, nil
+	}
+	if err != nil {
+		return ModuleConfig{}, err
+	}
+	if metadata.CheckAPI != nil {
+		return ModuleConfig{}, fmt.Errorf("%s: configuration overrides must be set in either metadata.yaml or %s, not both", folder, ModuleConfigFileName)
+	}
+	var moduleCfg ModuleConfig
+	if err = yaml.Unmarshal(b, &moduleCfg); err != nil {
+		return ModuleConfig{}, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return moduleCfg, nil
+}
+
+// Validate checks that the overrides only refer to known rules, and only disable the rules checking the lists
+// of the configuration file, which have nothing to turn on.
+func (m ModuleConfig) Validate() error {
+	var unknown []string
+	for rule := range m.Rules {
+		if !slices.Contains(Rules, rule) {
+			unknown = append(unknown, rule)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown rules %q, must be one of %q", unknown, Rules)
+	}
+	for _, rule := range []string{RuleAllowedFunctions, RuleAllowedTypes} {
+		if enabled, ok := m.Rules[rule]; ok && enabled {
+			return fmt.Errorf("rule %q can only be disabled, as it checks the lists of the configuration file", rule)
+		}
+	}
+	return nil
+}
+
+// WithOverrides returns a copy of the configuration with the overrides of a module applied.
+// Lists are appended to the global ones, and settings that are set replace the global ones.
+// Disabling a rule turns off its check for the module. Enabling it turns on its check with the global settings,
+// and makes breaking changes fail the check regardless of the version of the module.
+func (c Config) WithOverrides(m ModuleConfig) (Config, error) {
+	if err := m.Validate(); err != nil {
+		return Config{}, err
+	}
+	c.IgnoredFunctions = append(slices.Clip(c.IgnoredFunctions), m.IgnoredFunctions...)
+	c.ExcludedFiles = append(slices.Clip(c.ExcludedFiles), m.ExcludedFiles...)
+	if m.UnkeyedLiteral.Limit != nil {
+		c.UnkeyedLiteral.Limit = *m.UnkeyedLiteral.Limit
+	}
+	for rule, enabled := range m.Rules {
+		switch rule {
+		case RuleAllowedFunctions:
+			if !enabled {
+				c.AllowedFunctions = nil
+			}
+		case RuleAllowedTypes:
+			if !enabled {
+				c.AllowedTypes = nil
+			}
+		case RuleUnkeyedLiteral:
+			c.UnkeyedLiteral.Enabled = enabled
+		case RuleComponentAPI:
+			c.ComponentAPI = enabled
+			if !enabled {
+				c.ComponentAPIStrict = false
+			}
+		case RuleJSONSchema:
+			c.JSONSchema.CheckValid = enabled
+			if !enabled {
+				c.JSONSchema.CheckPresent = false
+				c.JSONSchema.CheckDefaults = false
+			}
+		case RuleEmbeddedConfigFields:
+			c.EmbeddedConfigFields.Enabled = enabled
+		case RuleDeprecations:
+			c.Deprecations.Enabled = enabled
+		case RuleExportedVariables:
+			c.ExportedVariables.Enabled = enabled
+		case RuleUnexportedReturns:
+			c.UnexportedReturnTypes = enabled
+		case RuleUnexportedParameters:
+			c.UnexportedParameterTypes = enabled
+		case RulePlatformSpecificAPI:
+			c.PlatformSpecificAPI = enabled
+		case RuleConfigConventions:
+			c.ConfigConventions.Enabled = enabled
+		case RuleCrossModuleImports:
+			c.CrossModuleInternalImports.Enabled = enabled
+		case RuleBreakingChanges:
+			c.BreakingChanges.Override = PolicyIgnore
+			if enabled {
+				c.BreakingChanges.Override = PolicyError
+			}
+		}
+	}
+	return c, nil
+}
+
+// ForModule returns the configuration of the module in folder, with the overrides of the module applied.
+func (c Config) ForModule(folder string, metadata Metadata) (Config, error) {
+	overrides, err := ReadModuleConfig(folder, metadata)
+	if err != nil {
+		return Config{}, err
+	}
+	moduleCfg, err := c.WithOverrides(overrides)
+	if err != nil {
+		return Config{}, fmt.Errorf("[%s] invalid configuration overrides: %w", folder, err)
+	}
+	return moduleCfg, nil
+}
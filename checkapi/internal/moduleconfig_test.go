@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOverrides(t *testing.T) {
+	limit := 2
+	cfg := Config{
+		IgnoredFunctions:   []string{"^Test"},
+		AllowedFunctions:   []FunctionDescription{{Name: "NewFactory"}},
+		AllowedTypes:       []TypeDescription{{Name: "Config"}},
+		UnkeyedLiteral:     UnkeyedLiteral{Enabled: true, Limit: 5},
+		ComponentAPIStrict: true,
+		JSONSchema:         JSONSchemaConfig{CheckValid: true},
+	}
+	overridden, err := cfg.WithOverrides(ModuleConfig{
+		Rules: map[string]bool{
+			RuleAllowedFunctions:     false,
+			RuleComponentAPI:         false,
+			RuleJSONSchema:           false,
+			RuleEmbeddedConfigFields: true,
+			RuleBreakingChanges:      false,
+		},
+		IgnoredFunctions: []string{"^Benchmark"},
+		UnkeyedLiteral:   UnkeyedLiteralOverride{Limit: &limit},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Config{
+		IgnoredFunctions:     []string{"^Test", "^Benchmark"},
+		AllowedTypes:         []TypeDescription{{Name: "Config"}},
+		UnkeyedLiteral:       UnkeyedLiteral{Enabled: true, Limit: 2},
+		EmbeddedConfigFields: EmbeddedConfigFields{Enabled: true},
+		BreakingChanges:      BreakingChangesConfig{Override: PolicyIgnore},
+	}, overridden)
+	assert.Equal(t, PolicyIgnore, overridden.BreakingChanges.Policy("stable", "v1.0.0"))
+	// the global configuration is left untouched.
+	assert.Equal(t, []string{"^Test"}, cfg.IgnoredFunctions)
+	assert.True(t, cfg.ComponentAPIStrict)
+
+	_, err = cfg.WithOverrides(ModuleConfig{Rules: map[string]bool{"unknown": true}})
+	require.ErrorContains(t, err, `unknown rules ["unknown"]`)
+
+	_, err = cfg.WithOverrides(ModuleConfig{Rules: map[string]bool{RuleAllowedTypes: true}})
+	require.EqualError(t, err, `rule "allowed_types" can only be disabled, as it checks the lists of the configuration file`)
+}
+
+func TestReadModuleConfig(t *testing.T) {
+	dir := t.TempDir()
+	moduleCfg, err := ReadModuleConfig(dir, Metadata{})
+	require.NoError(t, err)
+	assert.Equal(t, ModuleConfig{}, moduleCfg)
+
+	fromMetadata := &ModuleConfig{IgnoredFunctions: []string{"^Foo"}}
+	moduleCfg, err = ReadModuleConfig(dir, Metadata{CheckAPI: fromMetadata})
+	require.NoError(t, err)
+	assert.Equal(t, *fromMetadata, moduleCfg)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ModuleConfigFileName), []byte("unkeyed_literal_initialization:\n  limit: 3\n"), 0o600))
+	moduleCfg, err = ReadModuleConfig(dir, Metadata{})
+	require.NoError(t, err)
+	require.NotNil(t, moduleCfg.UnkeyedLiteral.Limit)
+	assert.Equal(t, 3, *moduleCfg.UnkeyedLiteral.Limit)
+
+	_, err = ReadModuleConfig(dir, Metadata{CheckAPI: fromMetadata})
+	require.ErrorContains(t, err, "not both")
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
+
+// Read loads the packages of the module in the specified folder, including their test files, and returns an API object.
+// Types are resolved by the type checker, and read from the syntax when they cannot be resolved,
+// for example when a dependency of the module is not available.
+func Read(folder string, ignoredFunctions []string, excludedFiles []string) (API, error) {
+	return read(folder, ignoredFunctions, excludedFiles, nil)
+}
+
+// read loads the packages of the module in the specified folder with additional environment variables,
+// such as GOOS and GOARCH, selecting the files matching their build constraints.
+func read(folder string, ignoredFunctions []string, excludedFiles []string, env []string) (API, error) {
+	absFolder, err := filepath.Abs(folder)
+	if err != nil {
+		return API{}, err
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  loadMode,
+		Dir:   folder,
+		Tests: true,
+		Env:   append(os.Environ(), env...),
+		// never update the go.mod and go.sum files of the module.
+		BuildFlags: []string{"-mod=readonly"},
+	}, "./...")
+	if err != nil {
+		return API{}, err
+	}
+
+	// a folder without a go.mod file only has the packages of its root folder in its module.
+	modulePath, _ := ReadModulePath(folder)
+	qualifier := moduleQualifier(absFolder, modulePath, pkgs)
+	result := &API{}
+	for _, pkg := range selectPackages(pkgs) {
+		var parseErrs []error
+		for _, e := range pkg.Errors {
+			if e.Kind == packages.ParseError {
+				parseErrs = append(parseErrs, e)
+			}
+		}
+		if len(parseErrs) > 0 {
+			return API{}, errors.Join(parseErrs...)
+		}
+
+		files, err := filterFiles(pkg, excludedFiles)
+		if err != nil {
+			return API{}, err
+		}
+		dir := pkg.Dir
+		if dir == "" && len(pkg.CompiledGoFiles) > 0 {
+			dir = filepath.Dir(pkg.CompiledGoFiles[0])
+		}
+		relativeDir, err := filepath.Rel(absFolder, dir)
+		if err != nil {
+			return API{}, err
+		}
+		isInternal := slices.Contains(strings.Split(filepath.Join(folder, relativeDir), string(os.PathSeparator)), "internal")
+		packageName := ""
+		if pkg.Types != nil {
+			packageName = qualifier(pkg.Types)
+		} else if relativeDir != "." {
+			packageName = filepath.ToSlash(relativeDir)
+		}
+		r := newPackageReader(pkg.TypesInfo, qualifier, filePosition(pkg.Fset, folder, absFolder), pkg.Syntax, ignoredFunctions, isInternal, packageName, result)
+		for _, f := range files {
+			r.readFile(f)
+		}
+	}
+	return *result, nil
+}
+
+// selectPackages keeps one variant of each package, preferring the one compiled for its tests
+// as it also holds the test files, and drops the generated test main packages.
+func selectPackages(pkgs []*packages.Package) []*packages.Package {
+	selected := map[string]*packages.Package{}
+	var paths []string
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		existing, ok := selected[pkg.PkgPath]
+		if !ok {
+			paths = append(paths, pkg.PkgPath)
+		}
+		if !ok || len(pkg.Syntax) > len(existing.Syntax) {
+			selected[pkg.PkgPath] = pkg
+		}
+	}
+	slices.Sort(paths)
+	result := make([]*packages.Package, len(paths))
+	for i, p := range paths {
+		result[i] = selected[p]
+	}
+	return result
+}
+
+func filterFiles(pkg *packages.Package, excludedFiles []string) ([]*ast.File, error) {
+	var files []*ast.File
+FILE:
+	for i, f := range pkg.Syntax {
+		path := pkg.Fset.File(f.FileStart).Name()
+		if i < len(pkg.CompiledGoFiles) {
+			path = pkg.CompiledGoFiles[i]
+		}
+		for _, exclusionPattern := range excludedFiles {
+			ok, err := filepath.Match(exclusionPattern, filepath.Base(path))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue FILE
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// filePosition returns the position of a declaration, with a file path under the folder of the module.
+func filePosition(fset *token.FileSet, folder string, absFolder string) func(token.Pos) Position {
+	return func(pos token.Pos) Position {
+		p := fset.Position(pos)
+		file := p.Filename
+		if rel, err := filepath.Rel(absFolder, file); err == nil {
+			file = filepath.Join(folder, rel)
+		}
+		return Position{File: file, Line: p.Line, Column: p.Column}
+	}
+}
+
+// moduleQualifier qualifies types with the import path of their package, relative to the module for the packages
+// of the module, so that types of different packages never have the same name. Types of the packages of the root
+// folder of the module are not qualified.
+func moduleQualifier(absFolder string, modulePath string, pkgs []*packages.Package) types.Qualifier {
+	root := map[string]struct{}{}
+	for _, pkg := range pkgs {
+		if pkg.Dir == absFolder {
+			root[pkg.PkgPath] = struct{}{}
+		}
+	}
+	return func(p *types.Package) string {
+		if _, ok := root[p.Path()]; ok {
+			return ""
+		}
+		if modulePath != "" && strings.HasPrefix(p.Path(), modulePath+"/") {
+			return strings.TrimPrefix(p.Path(), modulePath+"/")
+		}
+		return p.Path()
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	api, err := Read(filepath.Join("pkg", "pkg"), nil, nil)
+	require.NoError(t, err)
+	file := filepath.Join("pkg", "pkg", "pkg.go")
+	assert.Equal(t, []Function{
+		{Name: "SomeFunc", Params: []string{"string"}, ReturnTypes: []string{"bool"}, Pos: Position{File: file, Line: 15, Column: 6}},
+		{Name: "OtherFunc", Params: []string{"string"}, ReturnTypes: []string{"bool"}, Pos: Position{File: file, Line: 20, Column: 6}},
+	}, api.Functions)
+	assert.Equal(t, []APIinterface{{
+		Name:     "SomeInterface",
+		Embedded: []string{"fmt.Stringer"},
+		Methods: []Function{
+			{Name: "Do", Receiver: "SomeInterface", Params: []string{"string"}, ReturnTypes: []string{"error"}},
+			// promoted from fmt.Stringer
+			{Name: "String", Receiver: "SomeInterface", ReturnTypes: []string{"string"}},
+		},
+		Pos: Position{File: file, Line: 25, Column: 6},
+	}}, api.Interfaces)
+	assert.Equal(t, []APIstructField{{Name: "OneField", Type: "string", Doc: "OneField is a test field."}}, api.Structs[0].Fields)
+	assert.Equal(t, map[string][]string{"SomeType": {"some"}}, api.Enums)
+}
+
+func TestReadResolvesImportAliases(t *testing.T) {
+	api, err := Read(filepath.Join("config", "receiver", "aliasreceiver"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Config", api.ConfigStructName)
+	var cfg APIstruct
+	for _, s := range api.Structs {
+		if s.Name == "Config" {
+			cfg = s
+		}
+	}
+	// the package is imported as "embedded" but its name is "emb".
+	assert.Contains(t, cfg.Fields, APIstructField{Type: "emb.Config", Tag: "`mapstructure:\",squash\"`", Doc: "Embedded struct, imported with an alias"})
+}
+
+func TestReadMethods(t *testing.T) {
+	folder := filepath.Join("config", "receiver", "conventionsreceiver")
+	api, err := Read(folder, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]Function{
+		"ClientConfig": {{Name: "Validate", Receiver: "ClientConfig", ReturnTypes: []string{"error"}, Pos: Position{File: filepath.Join(folder, "code_test.go"), Line: 40, Column: 24}}},
+	}, api.Methods)
+}
+
+func TestReadExcludedFiles(t *testing.T) {
+	api, err := Read(filepath.Join("pkg", "pkg"), nil, []string{"pkg.go"})
+	require.NoError(t, err)
+	assert.Empty(t, api.Functions)
+	assert.Empty(t, api.Structs)
+}
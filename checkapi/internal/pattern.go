@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// variadicTail is the last entry of a list of type patterns that matches any remaining types.
+const variadicTail = "..."
+
+// matchPattern reports whether a name or a type matches a pattern.
+// A pattern enclosed in slashes, as in "/^New.*Factory$/", is a regular expression.
+// Otherwise, "*" in the pattern matches any sequence of characters, as in "New*Factory" or "*.Factory",
+// and the rest of the pattern must match exactly.
+func matchPattern(pattern string, s string) (bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.MatchString(pattern[1:len(pattern)-1], s)
+	}
+	if !strings.Contains(pattern, "*") {
+		return pattern == s, nil
+	}
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MatchString("^"+strings.Join(parts, ".*")+"$", s)
+}
+
+// matchTypes reports whether a list of types matches a list of type patterns.
+// If the last pattern is "...", it matches any number of remaining types.
+func matchTypes(patterns []string, types []string) (bool, error) {
+	if len(patterns) > 0 && patterns[len(patterns)-1] == variadicTail {
+		patterns = patterns[:len(patterns)-1]
+		if len(types) < len(patterns) {
+			return false, nil
+		}
+		types = types[:len(patterns)]
+	}
+	if len(patterns) != len(types) {
+		return false, nil
+	}
+	for i, p := range patterns {
+		if ok, err := matchType(p, types[i]); !ok || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchType reports whether a type in its canonical form, or in its short form, matches a pattern.
+func matchType(pattern string, t string) (bool, error) {
+	if ok, err := matchPattern(pattern, t); ok || err != nil {
+		return ok, err
+	}
+	if short := ShortTypeString(t); short != t {
+		return matchPattern(pattern, short)
+	}
+	return false, nil
+}
+
+// groupedTypes returns a list of types where consecutive entries of the same type are listed once,
+// as parameters sharing their type, as in "func(a, b string)", were listed by earlier versions.
+func groupedTypes(types []string) []string {
+	return slices.Compact(slices.Clone(types))
+}
+
+// Matches reports whether a function matches the name, parameters and return types of the description,
+// which may be patterns. Consecutive parameters or return types of the same type may be listed once.
+func (f FunctionDescription) Matches(fn Function) (bool, error) {
+	if ok, err := matchPattern(f.Name, fn.Name); !ok || err != nil {
+		return false, err
+	}
+	for _, types := range [][]string{fn.Params, groupedTypes(fn.Params)} {
+		ok, err := matchTypes(f.Parameters, types)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return matchTypes(f.ReturnTypes, fn.ReturnTypes)
+		}
+	}
+	return false, nil
+}
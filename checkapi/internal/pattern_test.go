@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchPattern(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		s       string
+		matches bool
+	}{
+		{pattern: "NewFactory", s: "NewFactory", matches: true},
+		{pattern: "NewFactory", s: "NewFactoryWithOptions"},
+		{pattern: "New*Factory", s: "NewFactory", matches: true},
+		{pattern: "New*Factory", s: "NewLogsFactory", matches: true},
+		{pattern: "New*Factory", s: "NewFactoryOptions"},
+		{pattern: "*.Factory", s: "receiver.Factory", matches: true},
+		{pattern: "*.Factory", s: "Factory"},
+		{pattern: "[]string", s: "[]string", matches: true},
+		{pattern: "map[string]*", s: "map[string]any", matches: true},
+		{pattern: "/^New(Logs|Traces)?Factory$/", s: "NewTracesFactory", matches: true},
+		{pattern: "/^New(Logs|Traces)?Factory$/", s: "NewMetricsFactory"},
+		{pattern: "/", s: "/", matches: true},
+	} {
+		t.Run(test.pattern+" "+test.s, func(t *testing.T) {
+			matches, err := matchPattern(test.pattern, test.s)
+			require.NoError(t, err)
+			assert.Equal(t, test.matches, matches)
+		})
+	}
+
+	_, err := matchPattern("/(/", "foo")
+	require.Error(t, err)
+}
+
+func TestFunctionDescriptionMatches(t *testing.T) {
+	fn := Function{
+		Name:        "NewFactory",
+		Params:      []string{"context.Context", "go.opentelemetry.io/collector/component.Type", "string", "string", "go.opentelemetry.io/collector/receiver.FactoryOption..."},
+		ReturnTypes: []string{"go.opentelemetry.io/collector/receiver.Factory"},
+	}
+	for _, test := range []struct {
+		name    string
+		desc    FunctionDescription
+		matches bool
+	}{
+		{
+			name:    "exact",
+			desc:    FunctionDescription{Name: "NewFactory", Parameters: []string{"context.Context", "go.opentelemetry.io/collector/component.Type", "string", "string", "go.opentelemetry.io/collector/receiver.FactoryOption..."}, ReturnTypes: []string{"go.opentelemetry.io/collector/receiver.Factory"}},
+			matches: true,
+		},
+		{
+			name:    "types qualified by the last element of their import path",
+			desc:    FunctionDescription{Name: "NewFactory", Parameters: []string{"context.Context", "component.Type", "string", "string", "receiver.FactoryOption..."}, ReturnTypes: []string{"receiver.Factory"}},
+			matches: true,
+		},
+		{
+			name:    "consecutive parameters of the same type listed once",
+			desc:    FunctionDescription{Name: "NewFactory", Parameters: []string{"context.Context", "component.Type", "string", "receiver.FactoryOption..."}, ReturnTypes: []string{"receiver.Factory"}},
+			matches: true,
+		},
+		{
+			name: "missing parameter",
+			desc: FunctionDescription{Name: "NewFactory", Parameters: []string{"context.Context", "component.Type", "string"}, ReturnTypes: []string{"receiver.Factory"}},
+		},
+		{
+			name:    "variadic tail",
+			desc:    FunctionDescription{Name: "New*", Parameters: []string{"context.Context", "..."}, ReturnTypes: []string{"*.Factory"}},
+			matches: true,
+		},
+		{
+			name:    "variadic tail matching no parameter",
+			desc:    FunctionDescription{Name: "NewFactory", Parameters: []string{"context.Context", "component.Type", "string", "string", "receiver.FactoryOption...", "..."}, ReturnTypes: []string{"*"}},
+			matches: true,
+		},
+		{
+			name: "variadic tail with too many parameters",
+			desc: FunctionDescription{Name: "NewFactory", Parameters: []string{"*", "*", "*", "*", "*", "*", "..."}, ReturnTypes: []string{"*"}},
+		},
+		{
+			name: "different return types",
+			desc: FunctionDescription{Name: "NewFactory", Parameters: []string{"..."}, ReturnTypes: []string{"*.Factory", "error"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			matches, err := test.desc.Matches(fn)
+			require.NoError(t, err)
+			assert.Equal(t, test.matches, matches)
+		})
+	}
+}
@@ -4,9 +4,11 @@
 // Package pkg is a test package used by checkapi tests.
 package pkg
 
+import "fmt"
+
 // SomeStruct is a test struct.
 type SomeStruct struct {
-	OneField string
+	OneField string // OneField is a test field.
 }
 
 // SomeFunc is a test function.
@@ -18,3 +20,22 @@ func SomeFunc(foo string) bool {
 func OtherFunc(bar string) bool {
 	return bar == "bar"
 }
+
+// SomeInterface is a test interface.
+type SomeInterface interface {
+	fmt.Stringer
+	Do(foo string) error
+}
+
+// SomeType is a test named type.
+type SomeType string
+
+// SomeAlias is a test type alias.
+type SomeAlias = SomeStruct
+
+// SomeValue is a test constant of a named string type.
+const SomeValue SomeType = "some"
+
+func (s SomeStruct) Describe(prefix string) string {
+	return prefix + s.OneField
+}
@@ -0,0 +1,3 @@
+module foo/bar/platformpkg/pkg
+
+go 1.25.0
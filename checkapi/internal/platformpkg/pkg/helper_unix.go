@@ -0,0 +1,12 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package pkg
+
+// UnixHelper is only declared on Unix platforms.
+func UnixHelper() {}
+
+// UnixOnly is only declared on Unix platforms.
+type UnixOnly struct{}
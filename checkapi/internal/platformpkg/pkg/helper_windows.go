@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pkg
+
+// WindowsHelper is only declared on Windows.
+func WindowsHelper() {}
@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pkg is a test package used by checkapi tests of platform-specific APIs.
+package pkg
+
+// Common is declared on every platform.
+func Common() {}
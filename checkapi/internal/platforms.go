@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// PlatformSpecificDeclaration is an exported declaration that is only declared on some of the target platforms.
+type PlatformSpecificDeclaration struct {
+	// Kind is the kind of the declaration: value, struct, interface, type or function.
+	Kind string
+	Name string
+	// Platforms are the platforms declaring it.
+	Platforms []string
+	Pos       Position
+}
+
+// ReadPlatforms reads the API of the module in the specified folder for each of the platforms, written GOOS/GOARCH,
+// honoring the build constraints and file name suffixes of each platform.
+// It returns the union of their APIs and the exported declarations missing on some of the platforms.
+func ReadPlatforms(folder string, ignoredFunctions []string, excludedFiles []string, platforms []string) (API, []PlatformSpecificDeclaration, error) {
+	apis := make([]API, len(platforms))
+	for i, platform := range platforms {
+		goos, goarch, ok := strings.Cut(platform, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return API{}, nil, fmt.Errorf("invalid platform %q, must be written GOOS/GOARCH", platform)
+		}
+		api, err := read(folder, ignoredFunctions, excludedFiles, []string{"GOOS=" + goos, "GOARCH=" + goarch})
+		if err != nil {
+			return API{}, nil, fmt.Errorf("cannot read %s for %s: %w", folder, platform, err)
+		}
+		apis[i] = api
+	}
+	return unionAPI(apis), platformSpecificDeclarations(platforms, apis), nil
+}
+
+// unionAPI merges the APIs read for several platforms. Declarations are identified by their kind and name,
+// and the first one read is kept.
+func unionAPI(apis []API) API {
+	var result API
+	seen := map[string]struct{}{}
+	add := func(key string) bool {
+		if _, ok := seen[key]; ok {
+			return false
+		}
+		seen[key] = struct{}{}
+		return true
+	}
+	for _, api := range apis {
+		for _, v := range api.Values {
+			if add("value " + v.Name) {
+				result.Values = append(result.Values, v)
+			}
+		}
+		for _, s := range api.Structs {
+			if add("struct " + s.Name) {
+				result.Structs = append(result.Structs, s)
+			}
+		}
+		for _, i := range api.Interfaces {
+			if add("interface " + i.Name) {
+				result.Interfaces = append(result.Interfaces, i)
+			}
+		}
+		for _, t := range api.Types {
+			if add("type " + t.Name) {
+				result.Types = append(result.Types, t)
+			}
+		}
+		for _, fn := range api.Functions {
+			if add("function " + functionKey(fn)) {
+				result.Functions = append(result.Functions, fn)
+			}
+		}
+		for _, d := range api.Deprecations {
+			if add("deprecation " + d.Name) {
+				result.Deprecations = append(result.Deprecations, d)
+			}
+		}
+		for name, values := range api.Enums {
+			if result.Enums == nil {
+				result.Enums = map[string][]string{}
+			}
+			for _, v := range values {
+				if add("enum " + name + " " + v) {
+					result.Enums[name] = append(result.Enums[name], v)
+				}
+			}
+		}
+		for name, methods := range api.Methods {
+			if result.Methods == nil {
+				result.Methods = map[string][]Function{}
+			}
+			for _, m := range methods {
+				if add("method " + name + "." + m.Name) {
+					result.Methods[name] = append(result.Methods[name], m)
+				}
+			}
+		}
+		for _, imp := range api.Imports {
+			if add("import " + imp.Path + " " + imp.Pos.String()) {
+				result.Imports = append(result.Imports, imp)
+			}
+		}
+		if result.ConfigStructName == "" {
+			result.ConfigStructName = api.ConfigStructName
+			result.ConfigDefaults = api.ConfigDefaults
+		}
+	}
+	return result
+}
+
+// platformSpecificDeclarations returns the exported declarations, outside of internal packages,
+// that are missing from the API of some of the platforms, sorted by kind and name.
+func platformSpecificDeclarations(platforms []string, apis []API) []PlatformSpecificDeclaration {
+	declarations := map[string]*PlatformSpecificDeclaration{}
+	add := func(platform string, kind string, name string, pos Position) {
+		key := kind + " " + name
+		d, ok := declarations[key]
+		if !ok {
+			d = &PlatformSpecificDeclaration{Kind: kind, Name: name, Pos: pos}
+			declarations[key] = d
+		}
+		if !slices.Contains(d.Platforms, platform) {
+			d.Platforms = append(d.Platforms, platform)
+		}
+	}
+	for i, api := range apis {
+		platform := platforms[i]
+		api = publicAPI(api)
+		for _, v := range api.Values {
+			add(platform, "value", v.Name, v.Pos)
+		}
+		for _, s := range api.Structs {
+			add(platform, "struct", s.Name, s.Pos)
+		}
+		for _, it := range api.Interfaces {
+			add(platform, "interface", it.Name, it.Pos)
+		}
+		for _, t := range api.Types {
+			add(platform, "type", t.Name, t.Pos)
+		}
+		for _, fn := range api.Functions {
+			add(platform, "function", functionKey(fn), fn.Pos)
+		}
+	}
+	distinct := len(slices.Compact(slices.Sorted(slices.Values(platforms))))
+	var result []PlatformSpecificDeclaration
+	for _, key := range slices.Sorted(maps.Keys(declarations)) {
+		if d := declarations[key]; len(d.Platforms) < distinct {
+			result = append(result, *d)
+		}
+	}
+	return result
+}
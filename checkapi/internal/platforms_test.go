@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPlatforms(t *testing.T) {
+	folder := filepath.Join("platformpkg", "pkg")
+	api, platformSpecific, err := ReadPlatforms(folder, nil, nil, []string{"linux/amd64", "windows/amd64"})
+	require.NoError(t, err)
+	var functions []string
+	for _, fn := range api.Functions {
+		functions = append(functions, fn.Name)
+	}
+	assert.ElementsMatch(t, []string{"Common", "UnixHelper", "WindowsHelper"}, functions)
+	assert.Equal(t, []PlatformSpecificDeclaration{
+		{Kind: "function", Name: "UnixHelper", Platforms: []string{"linux/amd64"}, Pos: Position{File: filepath.Join(folder, "helper_unix.go"), Line: 9, Column: 6}},
+		{Kind: "function", Name: "WindowsHelper", Platforms: []string{"windows/amd64"}, Pos: Position{File: filepath.Join(folder, "helper_windows.go"), Line: 7, Column: 6}},
+		{Kind: "struct", Name: "UnixOnly", Platforms: []string{"linux/amd64"}, Pos: Position{File: filepath.Join(folder, "helper_unix.go"), Line: 12, Column: 6}},
+	}, platformSpecific)
+
+	_, _, err = ReadPlatforms(folder, nil, nil, []string{"linux"})
+	require.EqualError(t, err, `invalid platform "linux", must be written GOOS/GOARCH`)
+}
+
+func TestUnionAPI(t *testing.T) {
+	assert.Equal(t, API{
+		Values:           []APIvalue{{Name: "A", Kind: ValueKindConst}, {Name: "B"}},
+		Functions:        []Function{{Name: "Do"}, {Name: "Do", Receiver: "Foo"}},
+		Enums:            map[string][]string{"Mode": {"a", "b"}},
+		ConfigStructName: "Config",
+	}, unionAPI([]API{
+		{Values: []APIvalue{{Name: "A", Kind: ValueKindConst}}, Functions: []Function{{Name: "Do"}}, Enums: map[string][]string{"Mode": {"a"}}},
+		{Values: []APIvalue{{Name: "A", Kind: ValueKindVar}, {Name: "B"}}, Functions: []Function{{Name: "Do", Receiver: "Foo"}}, Enums: map[string][]string{"Mode": {"a", "b"}}, ConfigStructName: "Config"},
+	}))
+}
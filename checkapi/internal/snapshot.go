@@ -0,0 +1,433 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SnapshotFileName is the name of the file holding the API snapshot of a module.
+const SnapshotFileName = "api.json"
+
+// BreakingChange is a change of the API that breaks its consumers.
+type BreakingChange struct {
+	Message string
+	// Pos is the position of the changed declaration, unset if it was removed.
+	Pos Position
+}
+
+// Snapshot returns the public part of the API without source positions, sorted so that it always serializes the same way.
+func Snapshot(api API) API {
+	result := publicAPI(api)
+	for i := range result.Values {
+		result.Values[i].Pos = Position{}
+	}
+	for i := range result.Structs {
+		result.Structs[i].Pos = Position{}
+	}
+	for i := range result.Interfaces {
+		result.Interfaces[i].Pos = Position{}
+	}
+	for i := range result.Types {
+		result.Types[i].Pos = Position{}
+	}
+	for i := range result.Functions {
+		result.Functions[i].Pos = Position{}
+	}
+	for _, methods := range result.Methods {
+		for i := range methods {
+			methods[i].Pos = Position{}
+		}
+	}
+	return result
+}
+
+// publicAPI returns the exported declarations of the API, outside of internal packages, sorted by name.
+func publicAPI(api API) API {
+	result := API{}
+	for _, v := range api.Values {
+		if !v.Internal && ast.IsExported(shortName(v.Name)) {
+			result.Values = append(result.Values, v)
+		}
+	}
+	sort.SliceStable(result.Values, func(i, j int) bool {
+		return result.Values[i].Name < result.Values[j].Name
+	})
+	result.Values = slices.CompactFunc(result.Values, func(a, b APIvalue) bool {
+		return a.Name == b.Name
+	})
+
+	for _, s := range api.Structs {
+		if s.Internal || !ast.IsExported(shortName(s.Name)) {
+			continue
+		}
+		var fields []APIstructField
+		for _, f := range s.Fields {
+			if isExportedField(f) {
+				// documentation changes do not change the API.
+				f.Doc = ""
+				fields = append(fields, f)
+			}
+		}
+		result.Structs = append(result.Structs, APIstruct{Name: s.Name, TypeParams: s.TypeParams, Fields: fields, Pos: s.Pos})
+	}
+	sort.SliceStable(result.Structs, func(i, j int) bool {
+		return result.Structs[i].Name < result.Structs[j].Name
+	})
+
+	for _, i := range api.Interfaces {
+		if i.Internal || !ast.IsExported(shortName(i.Name)) {
+			continue
+		}
+		snapshot := APIinterface{Name: i.Name, Embedded: slices.Sorted(slices.Values(i.Embedded)), Pos: i.Pos}
+		for _, m := range i.Methods {
+			m.Internal = false
+			snapshot.Methods = append(snapshot.Methods, m)
+		}
+		sort.SliceStable(snapshot.Methods, func(i, j int) bool {
+			return snapshot.Methods[i].Name < snapshot.Methods[j].Name
+		})
+		result.Interfaces = append(result.Interfaces, snapshot)
+	}
+	sort.SliceStable(result.Interfaces, func(i, j int) bool {
+		return result.Interfaces[i].Name < result.Interfaces[j].Name
+	})
+
+	for _, t := range api.Types {
+		if t.Internal || !ast.IsExported(shortName(t.Name)) {
+			continue
+		}
+		t.Internal = false
+		result.Types = append(result.Types, t)
+	}
+	sort.SliceStable(result.Types, func(i, j int) bool {
+		return result.Types[i].Name < result.Types[j].Name
+	})
+
+	for _, fn := range api.Functions {
+		if !fn.Internal {
+			result.Functions = append(result.Functions, fn)
+		}
+	}
+	sort.SliceStable(result.Functions, func(i, j int) bool {
+		if functionKey(result.Functions[i]) != functionKey(result.Functions[j]) {
+			return functionKey(result.Functions[i]) < functionKey(result.Functions[j])
+		}
+		return functionSignature(result.Functions[i]) < functionSignature(result.Functions[j])
+	})
+
+	for typeName, methods := range api.Methods {
+		if !ast.IsExported(shortName(typeName)) {
+			continue
+		}
+		var exported []Function
+		for _, m := range methods {
+			if !m.Internal && ast.IsExported(m.Name) {
+				exported = append(exported, m)
+			}
+		}
+		if len(exported) == 0 {
+			continue
+		}
+		sort.SliceStable(exported, func(i, j int) bool {
+			return exported[i].Name < exported[j].Name
+		})
+		if result.Methods == nil {
+			result.Methods = map[string][]Function{}
+		}
+		result.Methods[typeName] = exported
+	}
+	return result
+}
+
+// WriteSnapshot writes the public part of the API as JSON to the given path.
+func WriteSnapshot(path string, api API) error {
+	b, err := json.MarshalIndent(Snapshot(api), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// ReadSnapshot reads an API snapshot previously written with WriteSnapshot.
+func ReadSnapshot(path string) (API, error) {
+	b, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return API{}, err
+	}
+	var api API
+	if err = json.Unmarshal(b, &api); err != nil {
+		return API{}, fmt.Errorf("cannot read API snapshot %s: %w", path, err)
+	}
+	return api, nil
+}
+
+// UnmarshalJSON reads a value, also accepting the plain names that snapshots recorded before values had a kind and a type.
+func (v *APIvalue) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*v = APIvalue{Name: name}
+		return nil
+	}
+	type value APIvalue
+	return json.Unmarshal(b, (*value)(v))
+}
+
+// BreakingChanges lists the changes between two versions of an API that break its consumers:
+// removed values, types and functions, retyped values, changed function signatures, removed or retyped struct fields,
+// removed or changed methods of the types that remain, and changed interface method sets.
+func BreakingChanges(before API, after API) []BreakingChange {
+	before = publicAPI(before)
+	after = publicAPI(after)
+	var changes []BreakingChange
+	add := func(pos Position, messages ...string) {
+		for _, m := range messages {
+			changes = append(changes, BreakingChange{Message: m, Pos: pos})
+		}
+	}
+
+	afterValues := make(map[string]APIvalue, len(after.Values))
+	for _, v := range after.Values {
+		afterValues[v.Name] = v
+	}
+	for _, v := range before.Values {
+		av, ok := afterValues[v.Name]
+		switch {
+		case !ok:
+			add(Position{}, fmt.Sprintf("value %q removed", v.Name))
+		// snapshots written before values had a kind and a type only hold their name.
+		case v.Kind != "" && v.Kind != av.Kind:
+			add(av.Pos, fmt.Sprintf("value %q changed from %s to %s", v.Name, v.Kind, av.Kind))
+		case v.Type != "" && v.Type != av.Type:
+			add(av.Pos, fmt.Sprintf("value %q changed type from %q to %q", v.Name, v.Type, av.Type))
+		}
+	}
+
+	afterStructs := make(map[string]APIstruct, len(after.Structs))
+	for _, s := range after.Structs {
+		afterStructs[s.Name] = s
+	}
+	for _, s := range before.Structs {
+		as, ok := afterStructs[s.Name]
+		if !ok {
+			add(Position{}, fmt.Sprintf("struct %q removed", s.Name))
+			continue
+		}
+		add(as.Pos, fieldChanges(s, as)...)
+	}
+
+	afterInterfaces := make(map[string]APIinterface, len(after.Interfaces))
+	for _, i := range after.Interfaces {
+		afterInterfaces[i.Name] = i
+	}
+	for _, i := range before.Interfaces {
+		ai, ok := afterInterfaces[i.Name]
+		if !ok {
+			add(Position{}, fmt.Sprintf("interface %q removed", i.Name))
+			continue
+		}
+		add(ai.Pos, methodChanges(i, ai)...)
+	}
+
+	afterTypes := make(map[string]APItype, len(after.Types))
+	for _, t := range after.Types {
+		afterTypes[t.Name] = t
+	}
+	for _, t := range before.Types {
+		at, ok := afterTypes[t.Name]
+		switch {
+		case !ok:
+			add(Position{}, fmt.Sprintf("type %q removed", t.Name))
+		case at.Type != t.Type || at.Alias != t.Alias:
+			add(at.Pos, fmt.Sprintf("type %q changed from %q to %q", t.Name, typeDefinition(t), typeDefinition(at)))
+		}
+	}
+
+	afterFunctions := map[string][]string{}
+	afterFunctionsPos := map[string]Position{}
+	for _, fn := range after.Functions {
+		if _, ok := afterFunctions[functionKey(fn)]; !ok {
+			afterFunctionsPos[functionKey(fn)] = fn.Pos
+		}
+		afterFunctions[functionKey(fn)] = append(afterFunctions[functionKey(fn)], functionSignature(fn))
+	}
+	for _, fn := range before.Functions {
+		signatures, ok := afterFunctions[functionKey(fn)]
+		switch {
+		case !ok:
+			add(Position{}, fmt.Sprintf("function %q removed", functionKey(fn)))
+		case !slices.Contains(signatures, functionSignature(fn)):
+			add(afterFunctionsPos[functionKey(fn)], fmt.Sprintf("function %q changed from %q to %q", functionKey(fn), functionSignature(fn), strings.Join(signatures, ",")))
+		}
+	}
+
+	// the methods of removed types are reported with their type.
+	remaining := map[string]bool{}
+	for _, s := range after.Structs {
+		remaining[s.Name] = true
+	}
+	for _, t := range after.Types {
+		remaining[t.Name] = true
+	}
+	for _, typeName := range slices.Sorted(maps.Keys(before.Methods)) {
+		if !remaining[typeName] {
+			continue
+		}
+		afterMethods := make(map[string]Function, len(after.Methods[typeName]))
+		for _, m := range after.Methods[typeName] {
+			afterMethods[m.Name] = m
+		}
+		for _, m := range before.Methods[typeName] {
+			am, ok := afterMethods[m.Name]
+			switch {
+			case !ok:
+				add(Position{}, fmt.Sprintf("method %q of type %q removed", m.Name, typeName))
+			case functionSignature(am) != functionSignature(m):
+				add(am.Pos, fmt.Sprintf("method %q of type %q changed from %q to %q", m.Name, typeName, functionSignature(m), functionSignature(am)))
+			}
+		}
+	}
+	return changes
+}
+
+func fieldChanges(before APIstruct, after APIstruct) []string {
+	var changes []string
+	beforeTypes := fieldTypes(before)
+	afterTypes := fieldTypes(after)
+	names := make([]string, 0, len(beforeTypes))
+	for name := range beforeTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		at, ok := afterTypes[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("field %q of struct %q removed", name, before.Name))
+		case at != beforeTypes[name]:
+			changes = append(changes, fmt.Sprintf("field %q of struct %q changed type from %q to %q", name, before.Name, beforeTypes[name], at))
+		}
+	}
+	return changes
+}
+
+// methodChanges reports removed and changed methods and embedded types of an interface.
+// Additions are reported as well, since they break implementations of the interface,
+// unless the interface has unexported methods and therefore cannot be implemented outside its package.
+func methodChanges(before APIinterface, after APIinterface) []string {
+	var changes []string
+	beforeMethods := map[string]string{}
+	for _, m := range before.Methods {
+		beforeMethods[m.Name] = functionSignature(m)
+	}
+	afterMethods := map[string]string{}
+	for _, m := range after.Methods {
+		afterMethods[m.Name] = functionSignature(m)
+	}
+	for _, m := range before.Methods {
+		if !ast.IsExported(m.Name) {
+			continue
+		}
+		signature, ok := afterMethods[m.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("method %q of interface %q removed", m.Name, before.Name))
+		case signature != beforeMethods[m.Name]:
+			changes = append(changes, fmt.Sprintf("method %q of interface %q changed from %q to %q", m.Name, before.Name, beforeMethods[m.Name], signature))
+		}
+	}
+	for _, e := range before.Embedded {
+		if !slices.Contains(after.Embedded, e) {
+			changes = append(changes, fmt.Sprintf("embedded type %q of interface %q removed", e, before.Name))
+		}
+	}
+
+	sealed := false
+	for _, m := range after.Methods {
+		if !ast.IsExported(m.Name) {
+			sealed = true
+		}
+	}
+	if sealed {
+		return changes
+	}
+	for _, m := range after.Methods {
+		if _, ok := beforeMethods[m.Name]; !ok {
+			changes = append(changes, fmt.Sprintf("method %q added to interface %q", m.Name, before.Name))
+		}
+	}
+	for _, e := range after.Embedded {
+		if !slices.Contains(before.Embedded, e) {
+			changes = append(changes, fmt.Sprintf("embedded type %q added to interface %q", e, before.Name))
+		}
+	}
+	return changes
+}
+
+func typeDefinition(t APItype) string {
+	if t.Alias {
+		return "= " + t.Type
+	}
+	return t.Type
+}
+
+// fieldTypes maps each field name to its type. Embedded fields are named after their type.
+func fieldTypes(s APIstruct) map[string]string {
+	types := map[string]string{}
+	for _, f := range s.Fields {
+		name := f.Name
+		if name == "" {
+			name = f.Type
+		}
+		if existing, ok := types[name]; ok {
+			// map fields are recorded once for their key and once for their value.
+			types[name] = existing + "," + f.Type
+		} else {
+			types[name] = f.Type
+		}
+	}
+	return types
+}
+
+func isExportedField(f APIstructField) bool {
+	if f.Name == "" {
+		return ast.IsExported(shortName(f.Type))
+	}
+	return ast.IsExported(f.Name)
+}
+
+// shortName strips the package qualifier from a type or struct name.
+func shortName(name string) string {
+	segments := strings.Split(name, ".")
+	return segments[len(segments)-1]
+}
+
+func functionKey(fn Function) string {
+	if fn.Receiver != "" {
+		return fn.Receiver + "." + fn.Name
+	}
+	return fn.Name
+}
+
+func functionSignature(fn Function) string {
+	generics := ""
+	if len(fn.TypeParams) > 0 {
+		generics = fmt.Sprintf("[%s]", strings.Join(fn.TypeParams, ","))
+	}
+	if len(fn.ReturnTypes) == 0 {
+		return fmt.Sprintf("func%s(%s)", generics, strings.Join(fn.Params, ","))
+	}
+	return fmt.Sprintf("func%s(%s) %s", generics, strings.Join(fn.Params, ","), strings.Join(fn.ReturnTypes, ","))
+}
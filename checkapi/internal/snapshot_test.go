@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	api := API{
+		Values: []APIvalue{{Name: "B"}, {Name: "a"}, {Name: "A"}, {Name: "B"}},
+		Structs: []APIstruct{
+			{Name: "Foo", Fields: []APIstructField{{Name: "Bar", Type: "string"}, {Name: "baz", Type: "int"}, {Type: "Embedded"}, {Type: "pkg.embedded"}}},
+			{Name: "foo"},
+			{Name: "pkg.Bar"},
+			{Name: "Internal", Internal: true},
+		},
+		Functions: []Function{
+			{Name: "NewFoo"},
+			{Name: "InternalFunc", Internal: true},
+			{Name: "Do", Receiver: "Foo"},
+		},
+		Methods: map[string][]Function{
+			"Foo":         {{Name: "Send", Receiver: "Foo", Pos: Position{Line: 3}}, {Name: "Close", Receiver: "Foo"}, {Name: "reset", Receiver: "Foo"}},
+			"foo":         {{Name: "Send", Receiver: "foo"}},
+			"pkg.Bar":     {{Name: "internal", Receiver: "pkg.Bar"}},
+			"Internal":    {{Name: "Send", Receiver: "Internal", Internal: true}},
+			"pkg.Handler": {{Name: "Handle", Receiver: "pkg.Handler", Params: []string{"string"}}},
+		},
+		ConfigStructName: "Foo",
+	}
+	assert.Equal(t, API{
+		Values: []APIvalue{{Name: "A"}, {Name: "B"}},
+		Structs: []APIstruct{
+			{Name: "Foo", Fields: []APIstructField{{Name: "Bar", Type: "string"}, {Type: "Embedded"}}},
+			{Name: "pkg.Bar"},
+		},
+		Functions: []Function{
+			{Name: "Do", Receiver: "Foo"},
+			{Name: "NewFoo"},
+		},
+		Methods: map[string][]Function{
+			"Foo":         {{Name: "Close", Receiver: "Foo"}, {Name: "Send", Receiver: "Foo"}},
+			"pkg.Handler": {{Name: "Handle", Receiver: "pkg.Handler", Params: []string{"string"}}},
+		},
+	}, Snapshot(api))
+}
+
+func TestWriteReadSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "module", SnapshotFileName)
+	api := API{
+		Values:    []APIvalue{{Name: "Foo"}},
+		Structs:   []APIstruct{{Name: "Config", Fields: []APIstructField{{Name: "Endpoint", Type: "string", Tag: "`mapstructure:\"endpoint\"`"}}}},
+		Functions: []Function{{Name: "NewFactory", ReturnTypes: []string{"receiver.Factory"}}},
+	}
+	require.NoError(t, WriteSnapshot(path, api))
+	read, err := ReadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, api, read)
+	assert.Empty(t, BreakingChanges(read, api))
+}
+
+func TestReadLegacySnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SnapshotFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"values": ["Foo"]}`), 0o600))
+	read, err := ReadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, API{Values: []APIvalue{{Name: "Foo"}}}, read)
+}
+
+func TestBreakingChanges(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		before   API
+		after    API
+		expected []string
+	}{
+		{
+			name:   "identical",
+			before: API{Values: []APIvalue{{Name: "Foo"}}, Functions: []Function{{Name: "NewFactory"}}},
+			after:  API{Values: []APIvalue{{Name: "Foo"}}, Functions: []Function{{Name: "NewFactory"}}},
+		},
+		{
+			name:   "additions are not breaking",
+			before: API{Structs: []APIstruct{{Name: "Config"}}},
+			after: API{
+				Values:    []APIvalue{{Name: "Foo"}},
+				Structs:   []APIstruct{{Name: "Config", Fields: []APIstructField{{Name: "Endpoint", Type: "string"}}}},
+				Functions: []Function{{Name: "NewFactory"}},
+			},
+		},
+		{
+			name:     "removed value",
+			before:   API{Values: []APIvalue{{Name: "Foo"}, {Name: "Bar"}}},
+			after:    API{Values: []APIvalue{{Name: "Foo"}}},
+			expected: []string{`value "Bar" removed`},
+		},
+		{
+			name: "changed values",
+			before: API{Values: []APIvalue{
+				{Name: "Kind", Kind: ValueKindConst, Type: "untyped string"},
+				{Name: "Retyped", Kind: ValueKindVar, Type: "error"},
+				{Name: "Legacy"},
+			}},
+			after: API{Values: []APIvalue{
+				{Name: "Kind", Kind: ValueKindVar, Type: "string"},
+				{Name: "Retyped", Kind: ValueKindVar, Type: "*Error"},
+				{Name: "Legacy", Kind: ValueKindConst, Type: "int"},
+			}},
+			expected: []string{
+				`value "Kind" changed from const to var`,
+				`value "Retyped" changed type from "error" to "*Error"`,
+			},
+		},
+		{
+			name:     "removed struct",
+			before:   API{Structs: []APIstruct{{Name: "Config"}, {Name: "pkg.Other"}}},
+			after:    API{Structs: []APIstruct{{Name: "Config"}}},
+			expected: []string{`struct "pkg.Other" removed`},
+		},
+		{
+			name: "removed and changed fields",
+			before: API{Structs: []APIstruct{{Name: "Config", Fields: []APIstructField{
+				{Name: "Endpoint", Type: "string"},
+				{Name: "Timeout", Type: "time.Duration"},
+				{Type: "Embedded"},
+				{Name: "unexported", Type: "string"},
+			}}}},
+			after: API{Structs: []APIstruct{{Name: "Config", Fields: []APIstructField{
+				{Name: "Endpoint", Type: "int"},
+			}}}},
+			expected: []string{
+				`field "Embedded" of struct "Config" removed`,
+				`field "Endpoint" of struct "Config" changed type from "string" to "int"`,
+				`field "Timeout" of struct "Config" removed`,
+			},
+		},
+		{
+			name:     "removed function",
+			before:   API{Functions: []Function{{Name: "NewFactory"}, {Name: "Helper"}}},
+			after:    API{Functions: []Function{{Name: "NewFactory"}}},
+			expected: []string{`function "Helper" removed`},
+		},
+		{
+			name:     "changed function",
+			before:   API{Functions: []Function{{Name: "NewFactory", Params: []string{"string"}, ReturnTypes: []string{"receiver.Factory"}}}},
+			after:    API{Functions: []Function{{Name: "NewFactory", ReturnTypes: []string{"receiver.Factory", "error"}}}},
+			expected: []string{`function "NewFactory" changed from "func(string) receiver.Factory" to "func() receiver.Factory,error"`},
+		},
+		{
+			name: "changed interfaces",
+			before: API{Interfaces: []APIinterface{
+				{Name: "Host", Methods: []Function{{Name: "GetExtensions"}, {Name: "Start", Params: []string{"context.Context"}}}, Embedded: []string{"fmt.Stringer"}},
+				{Name: "Removed"},
+			}},
+			after: API{Interfaces: []APIinterface{
+				{Name: "Host", Methods: []Function{{Name: "Start"}, {Name: "Shutdown"}}, Embedded: []string{"io.Closer"}},
+			}},
+			expected: []string{
+				`method "GetExtensions" of interface "Host" removed`,
+				`method "Start" of interface "Host" changed from "func(context.Context)" to "func()"`,
+				`embedded type "fmt.Stringer" of interface "Host" removed`,
+				`method "Shutdown" added to interface "Host"`,
+				`embedded type "io.Closer" added to interface "Host"`,
+				`interface "Removed" removed`,
+			},
+		},
+		{
+			name:   "methods added to sealed interfaces are not breaking",
+			before: API{Interfaces: []APIinterface{{Name: "Host", Methods: []Function{{Name: "private"}}}}},
+			after:  API{Interfaces: []APIinterface{{Name: "Host", Methods: []Function{{Name: "Start"}, {Name: "private"}}}}},
+		},
+		{
+			name:   "changed types",
+			before: API{Types: []APItype{{Name: "ID", Type: "string"}, {Name: "Alias", Type: "Config", Alias: true}, {Name: "Removed", Type: "int"}}},
+			after:  API{Types: []APItype{{Name: "ID", Type: "[]byte"}, {Name: "Alias", Type: "Config"}}},
+			expected: []string{
+				`type "Alias" changed from "= Config" to "Config"`,
+				`type "ID" changed from "string" to "[]byte"`,
+				`type "Removed" removed`,
+			},
+		},
+		{
+			name: "removed and changed methods",
+			before: API{
+				Structs: []APIstruct{{Name: "Client"}, {Name: "Removed"}},
+				Types:   []APItype{{Name: "ID", Type: "string"}},
+				Methods: map[string][]Function{
+					"Client":  {{Name: "Send", Params: []string{"string"}}, {Name: "Close"}, {Name: "Flush"}, {Name: "reset"}},
+					"ID":      {{Name: "String", ReturnTypes: []string{"string"}}},
+					"Removed": {{Name: "Do"}},
+				},
+			},
+			after: API{
+				Structs: []APIstruct{{Name: "Client"}},
+				Types:   []APItype{{Name: "ID", Type: "string"}},
+				Methods: map[string][]Function{
+					"Client": {{Name: "Send", Params: []string{"[]byte"}}, {Name: "Flush"}, {Name: "Open"}},
+				},
+			},
+			expected: []string{
+				`struct "Removed" removed`,
+				`method "Close" of type "Client" removed`,
+				`method "Send" of type "Client" changed from "func(string)" to "func([]byte)"`,
+				`method "String" of type "ID" removed`,
+			},
+		},
+		{
+			name:   "internal APIs are ignored",
+			before: API{Functions: []Function{{Name: "Helper", Internal: true}}, Structs: []APIstruct{{Name: "Config", Internal: true}}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var messages []string
+			for _, c := range BreakingChanges(test.before, test.after) {
+				messages = append(messages, c.Message)
+			}
+			assert.Equal(t, test.expected, messages)
+		})
+	}
+}
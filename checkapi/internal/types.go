@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// TypeString returns the canonical representation of a type: aliases are resolved, and types declared
+// outside of the root package are qualified with the import path of their package, regardless of how it was imported.
+// Parameter names are omitted from function signatures.
+func TypeString(t types.Type, qualifier types.Qualifier) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		name := t.Obj().Name()
+		if t.Obj().Pkg() != nil {
+			if q := qualifier(t.Obj().Pkg()); q != "" {
+				name = q + "." + name
+			}
+		}
+		if t.TypeArgs().Len() == 0 {
+			return name
+		}
+		args := make([]string, t.TypeArgs().Len())
+		for i := range t.TypeArgs().Len() {
+			args[i] = TypeString(t.TypeArgs().At(i), qualifier)
+		}
+		return fmt.Sprintf("%s[%s]", name, strings.Join(args, ","))
+	case *types.Pointer:
+		return "*" + TypeString(t.Elem(), qualifier)
+	case *types.Slice:
+		return "[]" + TypeString(t.Elem(), qualifier)
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), TypeString(t.Elem(), qualifier))
+	case *types.Map:
+		return fmt.Sprintf("map[%s]%s", TypeString(t.Key(), qualifier), TypeString(t.Elem(), qualifier))
+	case *types.Chan:
+		switch t.Dir() {
+		case types.SendOnly:
+			return "chan<- " + TypeString(t.Elem(), qualifier)
+		case types.RecvOnly:
+			return "<-chan " + TypeString(t.Elem(), qualifier)
+		default:
+			return "chan " + TypeString(t.Elem(), qualifier)
+		}
+	case *types.Signature:
+		params, results := signatureStrings(t, qualifier)
+		switch len(results) {
+		case 0:
+			return fmt.Sprintf("func(%s)", strings.Join(params, ","))
+		case 1:
+			return fmt.Sprintf("func(%s) %s", strings.Join(params, ","), results[0])
+		default:
+			return fmt.Sprintf("func(%s) (%s)", strings.Join(params, ","), strings.Join(results, ","))
+		}
+	default:
+		return types.TypeString(t, qualifier)
+	}
+}
+
+// signatureStrings returns the canonical representation of the parameter and result types of a function.
+// The variadic parameter is written as "T...".
+func signatureStrings(sig *types.Signature, qualifier types.Qualifier) ([]string, []string) {
+	params := tupleStrings(sig.Params(), qualifier)
+	if sig.Variadic() && len(params) > 0 {
+		params[len(params)-1] = strings.TrimPrefix(params[len(params)-1], "[]") + "..."
+	}
+	return params, tupleStrings(sig.Results(), qualifier)
+}
+
+func tupleStrings(tuple *types.Tuple, qualifier types.Qualifier) []string {
+	if tuple.Len() == 0 {
+		return nil
+	}
+	result := make([]string, tuple.Len())
+	for i := range tuple.Len() {
+		result[i] = TypeString(tuple.At(i).Type(), qualifier)
+	}
+	return result
+}
+
+// isValidType reports whether a type was fully resolved by the type checker.
+func isValidType(t types.Type) bool {
+	return t != nil && !strings.Contains(types.TypeString(t, nil), "invalid type")
+}
+
+// typeNamePattern matches the possibly qualified names in a canonical type string.
+var typeNamePattern = regexp.MustCompile(`[\pL_][\pL\pN_.]*`)
+
+// qualifiedNamePattern matches the qualified names of a canonical type string, as "component.Config"
+// or "go.opentelemetry.io/collector/component.Config".
+var qualifiedNamePattern = regexp.MustCompile(`(?:[\pL\pN_.~-]+/)*[\pL\pN_.~-]+\.[\pL_][\pL\pN_]*`)
+
+// majorVersionPattern matches the major version suffix of a module path, as "v2".
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// ShortTypeString returns a canonical type string with its types qualified by the last element of the import path
+// of their package, without its major version suffix, as in "component.Config": the form of the types in
+// configurations written for earlier versions, which qualified types with the name of their package.
+func ShortTypeString(t string) string {
+	return qualifiedNamePattern.ReplaceAllStringFunc(t, func(name string) string {
+		i := strings.LastIndex(name, ".")
+		elements := strings.Split(name[:i], "/")
+		pkg := elements[len(elements)-1]
+		if majorVersionPattern.MatchString(pkg) && len(elements) > 1 {
+			pkg = elements[len(elements)-2]
+		}
+		// gopkg.in paths carry their major version as a suffix, as in "gopkg.in/yaml.v3".
+		pkg, _, _ = strings.Cut(pkg, ".")
+		return pkg + name[i:]
+	})
+}
+
+// typeParamPattern matches the type parameters of a type pattern, as in "Optional[$T]".
+var typeParamPattern = regexp.MustCompile(`\$[\pL_][\pL\pN_]*`)
+
+// substituteTypeParams replaces the type parameters of a canonical type string by the types they are bound to.
+func substituteTypeParams(t string, bindings map[string]string) string {
+	if len(bindings) == 0 {
+		return t
+	}
+	return typeNamePattern.ReplaceAllStringFunc(t, func(name string) string {
+		if bound, ok := bindings[name]; ok {
+			return bound
+		}
+		return name
+	})
+}
+
+// splitTypeArgs splits an instantiated generic type, as in "Foo[Bar,map[string]Baz]", into its name and its type arguments.
+func splitTypeArgs(t string) (string, []string) {
+	start := strings.Index(t, "[")
+	if start <= 0 || !strings.HasSuffix(t, "]") {
+		return t, nil
+	}
+	return t[:start], splitTopLevel(t[start+1 : len(t)-1])
+}
+
+// splitMapType splits a map type into its key and value types.
+func splitMapType(t string) (string, string, bool) {
+	if !strings.HasPrefix(t, "map[") {
+		return "", "", false
+	}
+	depth := 0
+	for i := len("map"); i < len(t); i++ {
+		switch t[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return t[len("map["):i], t[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// splitTopLevel splits a list of types on the commas that are not nested in brackets, braces or parentheses.
+func splitTopLevel(list string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, c := range list {
+		switch c {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, list[start:])
+}
+
+// matchTypePattern matches a canonical type string against a pattern in which type parameters, as in "Optional[$T]",
+// match any type, and returns the types bound to each type parameter. Types of the pattern may be qualified with
+// the last element of the import path of their package, as in "configoptional.Optional[$T]".
+func matchTypePattern(pattern string, t string) (map[string]string, bool) {
+	var params []string
+	var expr strings.Builder
+	last := 0
+	for _, loc := range typeParamPattern.FindAllStringIndex(pattern, -1) {
+		expr.WriteString(typeLiteralExpr(pattern[last:loc[0]]) + "(.+)")
+		params = append(params, pattern[loc[0]+1:loc[1]])
+		last = loc[1]
+	}
+	expr.WriteString(typeLiteralExpr(pattern[last:]))
+	re, err := regexp.Compile("^" + expr.String() + "$")
+	if err != nil {
+		return nil, false
+	}
+	match := re.FindStringSubmatch(t)
+	if match == nil {
+		return nil, false
+	}
+	bindings := make(map[string]string, len(params))
+	for i, p := range params {
+		if bound, ok := bindings[p]; ok && bound != match[i+1] {
+			return nil, false
+		}
+		bindings[p] = match[i+1]
+	}
+	return bindings, true
+}
+
+// typeLiteralExpr returns a regular expression matching a part of a type pattern without type parameters,
+// in which types qualified with the last element of the import path of their package also match the types
+// qualified with the full import path, with or without its major version suffix.
+func typeLiteralExpr(literal string) string {
+	var expr strings.Builder
+	last := 0
+	for _, loc := range qualifiedNamePattern.FindAllStringIndex(literal, -1) {
+		expr.WriteString(regexp.QuoteMeta(literal[last:loc[0]]))
+		name := literal[loc[0]:loc[1]]
+		i := strings.LastIndex(name, ".")
+		if strings.Contains(name[:i], "/") {
+			expr.WriteString(regexp.QuoteMeta(name))
+		} else {
+			expr.WriteString(`(?:[\pL\pN_.~-]+/)*` + regexp.QuoteMeta(name[:i]) + `(?:/v[0-9]+|\.v[0-9]+)?` + regexp.QuoteMeta(name[i:]))
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(literal[last:]))
+	return expr.String()
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeString(t *testing.T) {
+	root := types.NewPackage("example.com/component", "component")
+	other := types.NewPackage("example.com/pdata/v2", "pdata")
+	config := types.NewNamed(types.NewTypeName(token.NoPos, root, "Config", nil), types.NewStruct(nil, nil), nil)
+	logs := types.NewNamed(types.NewTypeName(token.NoPos, other, "Logs", nil), types.NewStruct(nil, nil), nil)
+	alias := types.NewAlias(types.NewTypeName(token.NoPos, root, "LogsAlias", nil), logs)
+	qualifier := func(p *types.Package) string {
+		if p == root {
+			return ""
+		}
+		return p.Path()
+	}
+	param := func(t types.Type) *types.Var {
+		return types.NewParam(token.NoPos, nil, "name", t)
+	}
+
+	for _, test := range []struct {
+		name     string
+		t        types.Type
+		expected string
+	}{
+		{
+			name:     "basic",
+			t:        types.Typ[types.String],
+			expected: "string",
+		},
+		{
+			name:     "root package",
+			t:        types.NewPointer(config),
+			expected: "*Config",
+		},
+		{
+			name:     "other package qualified by its import path",
+			t:        types.NewSlice(logs),
+			expected: "[]example.com/pdata/v2.Logs",
+		},
+		{
+			name:     "alias resolved",
+			t:        types.NewMap(types.Typ[types.String], alias),
+			expected: "map[string]example.com/pdata/v2.Logs",
+		},
+		{
+			name:     "channel",
+			t:        types.NewChan(types.RecvOnly, logs),
+			expected: "<-chan example.com/pdata/v2.Logs",
+		},
+		{
+			name: "signature without parameter names",
+			t: types.NewSignatureType(nil, nil, nil,
+				types.NewTuple(param(config), param(types.NewSlice(types.Typ[types.String]))),
+				types.NewTuple(param(types.Universe.Lookup("error").Type())),
+				true),
+			expected: "func(Config,string...) error",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, TypeString(test.t, qualifier))
+		})
+	}
+}
+
+func TestShortTypeString(t *testing.T) {
+	for _, test := range []struct {
+		t        string
+		expected string
+	}{
+		{t: "string", expected: "string"},
+		{t: "*Config", expected: "*Config"},
+		{t: "internal/metadata.Config", expected: "metadata.Config"},
+		{t: "[]go.opentelemetry.io/collector/component.Config", expected: "[]component.Config"},
+		{t: "map[string]example.com/pdata/v2.Logs", expected: "map[string]pdata.Logs"},
+		{t: "*gopkg.in/yaml.v3.Node", expected: "*yaml.Node"},
+		{t: "func(context.Context,net/http.Handler) error", expected: "func(context.Context,http.Handler) error"},
+		{
+			t:        "go.opentelemetry.io/collector/config/configoptional.Optional[go.opentelemetry.io/collector/config/confighttp.ServerConfig]",
+			expected: "configoptional.Optional[confighttp.ServerConfig]",
+		},
+	} {
+		t.Run(test.t, func(t *testing.T) {
+			assert.Equal(t, test.expected, ShortTypeString(test.t))
+		})
+	}
+}
+
+func TestSplitTypes(t *testing.T) {
+	name, args := splitTypeArgs("configoptional.Optional[map[string]Foo[Bar,Baz],int]")
+	assert.Equal(t, "configoptional.Optional", name)
+	assert.Equal(t, []string{"map[string]Foo[Bar,Baz]", "int"}, args)
+
+	name, args = splitTypeArgs("[]Foo[Bar]")
+	assert.Equal(t, "[]Foo[Bar]", name)
+	assert.Nil(t, args)
+
+	key, value, ok := splitMapType("map[Key[K]][]Value")
+	assert.True(t, ok)
+	assert.Equal(t, "Key[K]", key)
+	assert.Equal(t, "[]Value", value)
+
+	_, _, ok = splitMapType("[]string")
+	assert.False(t, ok)
+
+	assert.Equal(t, "map[string][]pkg.T", substituteTypeParams("map[K][]pkg.T", map[string]string{"K": "string", "T": "int"}))
+}
+
+func TestMatchTypePattern(t *testing.T) {
+	for _, test := range []struct {
+		pattern  string
+		t        string
+		bindings map[string]string
+	}{
+		{pattern: "configoptional.Optional[$T]", t: "configoptional.Optional[Config]", bindings: map[string]string{"T": "Config"}},
+		{pattern: "configoptional.Optional[$T]", t: "configoptional.Optional[[]*confighttp.ServerConfig]", bindings: map[string]string{"T": "[]*confighttp.ServerConfig"}},
+		{pattern: "Pair[$T,$T]", t: "Pair[int,int]", bindings: map[string]string{"T": "int"}},
+		{pattern: "Pair[$T,$T]", t: "Pair[int,string]"},
+		{pattern: "configoptional.Optional[$T]", t: "configoptionalXOptional[Config]"},
+		{pattern: "Optional[$T]", t: "[]Optional[Config]"},
+		{
+			pattern:  "configoptional.Optional[$T]",
+			t:        "go.opentelemetry.io/collector/config/configoptional.Optional[go.opentelemetry.io/collector/config/confighttp.ServerConfig]",
+			bindings: map[string]string{"T": "go.opentelemetry.io/collector/config/confighttp.ServerConfig"},
+		},
+		{pattern: "pdata.Logs[$T]", t: "example.com/pdata/v2.Logs[int]", bindings: map[string]string{"T": "int"}},
+		{pattern: "yaml.Node[$T]", t: "gopkg.in/yaml.v3.Node[int]", bindings: map[string]string{"T": "int"}},
+		{pattern: "configoptional.Optional[$T]", t: "example.com/notconfigoptional.Optional[int]"},
+	} {
+		t.Run(test.pattern+" "+test.t, func(t *testing.T) {
+			bindings, ok := matchTypePattern(test.pattern, test.t)
+			assert.Equal(t, test.bindings != nil, ok)
+			assert.Equal(t, test.bindings, bindings)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PolicyError fails the check on breaking changes.
+	PolicyError = "error"
+	// PolicyWarn reports breaking changes without failing the check.
+	PolicyWarn = "warn"
+	// PolicyIgnore does not report breaking changes.
+	PolicyIgnore = "ignore"
+)
+
+// ModuleSet is a set of modules released together under the same version.
+type ModuleSet struct {
+	Version string   `yaml:"version"`
+	Modules []string `yaml:"modules"`
+}
+
+// Versions represents the module sets of a multimod versions.yaml file.
+type Versions struct {
+	ModuleSets      map[string]ModuleSet `yaml:"module-sets"`
+	ExcludedModules []string             `yaml:"excluded-modules"`
+}
+
+// ReadVersions reads a multimod versions.yaml file.
+func ReadVersions(path string) (Versions, error) {
+	b, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return Versions{}, err
+	}
+	var versions Versions
+	if err = yaml.Unmarshal(b, &versions); err != nil {
+		return Versions{}, fmt.Errorf("cannot read versions file %s: %w", path, err)
+	}
+	for name, set := range versions.ModuleSets {
+		if !semver.IsValid(set.Version) {
+			return Versions{}, fmt.Errorf("module set %q has an invalid version %q", name, set.Version)
+		}
+	}
+	return versions, nil
+}
+
+// ModuleSetOf returns the name and version of the module set containing the module.
+func (v Versions) ModuleSetOf(modulePath string) (string, string, bool) {
+	for name, set := range v.ModuleSets {
+		for _, m := range set.Modules {
+			if m == modulePath {
+				return name, set.Version, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// IsStableVersion returns true if the major version is greater than or equal to v1.
+func IsStableVersion(v string) bool {
+	return semver.Compare(semver.Major(v), "v1") >= 0
+}
+
+// ReadModulePath returns the module path declared in the go.mod file of the folder.
+func ReadModulePath(folder string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(folder, "go.mod")) // #nosec G304
+	if err != nil {
+		return "", err
+	}
+	modulePath := modfile.ModulePath(b)
+	if modulePath == "" {
+		return "", fmt.Errorf("no module path found in %s", filepath.Join(folder, "go.mod"))
+	}
+	return modulePath, nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`module-sets:
+  stable:
+    version: v1.30.0
+    modules:
+      - go.opentelemetry.io/collector/pdata
+  beta:
+    version: v0.124.0
+    modules:
+      - go.opentelemetry.io/collector/receiver/otlpreceiver
+excluded-modules:
+  - go.opentelemetry.io/collector/internal/tools
+`), 0o600))
+	versions, err := ReadVersions(path)
+	require.NoError(t, err)
+
+	name, version, ok := versions.ModuleSetOf("go.opentelemetry.io/collector/pdata")
+	assert.True(t, ok)
+	assert.Equal(t, "stable", name)
+	assert.Equal(t, "v1.30.0", version)
+
+	name, version, ok = versions.ModuleSetOf("go.opentelemetry.io/collector/receiver/otlpreceiver")
+	assert.True(t, ok)
+	assert.Equal(t, "beta", name)
+	assert.Equal(t, "v0.124.0", version)
+
+	_, _, ok = versions.ModuleSetOf("go.opentelemetry.io/collector/internal/tools")
+	assert.False(t, ok)
+}
+
+func TestReadVersionsInvalidVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`module-sets:
+  stable:
+    version: 1.30
+`), 0o600))
+	_, err := ReadVersions(path)
+	require.EqualError(t, err, `module set "stable" has an invalid version "1.30"`)
+}
+
+func TestReadModulePath(t *testing.T) {
+	modulePath, err := ReadModulePath(filepath.Join("pkg", "pkg"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo/bar/pkg/pkg", modulePath)
+
+	_, err = ReadModulePath("pkg")
+	require.Error(t, err)
+}
+
+func TestBreakingChangesPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		cfg       BreakingChangesConfig
+		moduleSet string
+		version   string
+		expected  string
+	}{
+		{
+			name:      "stable set",
+			moduleSet: "stable",
+			version:   "v1.30.0",
+			expected:  PolicyError,
+		},
+		{
+			name:      "unstable set",
+			moduleSet: "beta",
+			version:   "v0.124.0",
+			expected:  PolicyWarn,
+		},
+		{
+			name:     "no module set",
+			expected: PolicyWarn,
+		},
+		{
+			name:      "configured defaults",
+			cfg:       BreakingChangesConfig{Stable: PolicyWarn, Unstable: PolicyIgnore},
+			moduleSet: "stable",
+			version:   "v2.0.0",
+			expected:  PolicyWarn,
+		},
+		{
+			name:      "module set override",
+			cfg:       BreakingChangesConfig{ModuleSets: map[string]string{"beta": PolicyError}},
+			moduleSet: "beta",
+			version:   "v0.124.0",
+			expected:  PolicyError,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.cfg.Policy(test.moduleSet, test.version))
+		})
+	}
+}
@@ -8,318 +8,419 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"go/ast"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
-	"slices"
-	"sort"
+	"runtime"
 	"strings"
-	"unicode"
-
-	"github.com/goccy/go-json"
-	"github.com/kaptinlin/jsonschema"
+	"sync"
 
 	"go.opentelemetry.io/build-tools/checkapi/internal"
+)
 
-	"gopkg.in/yaml.v3"
+var (
+	// parallelism is the maximum number of modules processed concurrently.
+	parallelism = runtime.GOMAXPROCS(0)
+	// apiCache stores the API read for modules across runs, if set.
+	apiCache *internal.Cache
 )
 
 func main() {
 	folder := flag.String("folder", ".", "folder investigated for modules")
 	configPath := flag.String("config", "cmd/checkapi/config.yaml", "configuration file")
+	writeSnapshotsDir := flag.String("write-snapshots", "", "write the API of each module as a JSON snapshot under this folder instead of checking it")
+	diffSnapshotsDir := flag.String("diff-snapshots", "", "report breaking changes between the API of each module and its JSON snapshot under this folder")
+	fix := flag.Bool("fix", false, "rewrite the config section of the metadata.yaml file of each component with the JSON schema derived from its config struct instead of checking it")
+	versionsPath := flag.String("versions", "", "multimod versions.yaml file used to apply breaking change policies and the deprecation lifecycle per module set")
+	format := flag.String("format", internal.FormatText, "output format of the diagnostics: text, json, sarif or github")
+	baseRef := flag.String("base-ref", "", "only check or diff the modules affected by the changes since the merge base of this git ref and HEAD")
+	flag.IntVar(&parallelism, "parallelism", parallelism, "maximum number of modules processed concurrently")
+	cacheDir := flag.String("cache", "", "folder caching the API read for modules, keyed by a hash of their Go files and of the configuration")
+	changedFilesPath := flag.String("changed-files", "", "only check or diff the modules affected by the files listed in this file, one per line, relative to the folder")
+	baselinePath := flag.String("baseline", "", "baseline file of known violations, which do not fail the check")
+	writeBaseline := flag.Bool("write-baseline", false, "record all the current violations in the baseline file")
+	pruneBaseline := flag.Bool("prune-baseline", false, "remove the violations that no longer occur from the baseline file")
 	flag.Parse()
-	if err := run(*folder, *configPath); err != nil {
+	if parallelism < 1 {
+		log.Fatalf("invalid parallelism %d, must be at least 1", parallelism)
+	}
+	if *cacheDir != "" {
+		apiCache = internal.NewCache(*cacheDir)
+	}
+	var diags []internal.Diagnostic
+	affected, err := affectedModules(*folder, *baseRef, *changedFilesPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch {
+	case *fix:
+		err = fixSchemas(*folder, *configPath)
+	case *writeSnapshotsDir != "":
+		err = writeSnapshots(*folder, *configPath, *writeSnapshotsDir)
+	case *diffSnapshotsDir != "":
+		diags, err = diffSnapshots(*folder, *configPath, *diffSnapshotsDir, *versionsPath, affected)
+	default:
+		diags, err = check(*folder, *configPath, *versionsPath, affected)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *baselinePath != "" {
+		diags, err = applyBaseline(*folder, *baselinePath, *writeBaseline, *pruneBaseline, affected != nil, diags)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else if *writeBaseline || *pruneBaseline {
+		log.Fatal("-write-baseline and -prune-baseline require -baseline")
+	}
+	if err = internal.WriteDiagnostics(os.Stdout, *format, diags); err != nil {
 		log.Fatal(err)
 	}
+	if internal.DiagnosticsError(diags) != nil {
+		os.Exit(1)
+	}
 }
 
+// run checks the modules under folder and returns the diagnostics with an error severity as an error.
 func run(folder string, configPath string) error {
-	configData, err := os.ReadFile(configPath) // #nosec G304
+	diags, err := check(folder, configPath, "", nil)
 	if err != nil {
 		return err
 	}
-	var cfg internal.Config
-	err = yaml.Unmarshal(configData, &cfg)
+	return internal.DiagnosticsError(diags)
+}
+
+// check checks the modules under folder against the configuration and returns the diagnostics found.
+// If versionsPath is set, deprecated declarations are checked against the version of the module set of each module.
+// If affected is set, only the affected modules are checked.
+// The error is only set if the modules could not be checked.
+func check(folder string, configPath string, versionsPath string, affected *internal.AffectedModules) ([]internal.Diagnostic, error) {
+	cfg, err := internal.ReadConfig(configPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var errs []error
-	err = filepath.Walk(folder, func(path string, info fs.FileInfo, _ error) error {
-		if info.Name() == "go.mod" {
-			base := filepath.Dir(path)
-			relativeBase, err2 := filepath.Rel(folder, base)
-			if err2 != nil {
-				return err2
-			}
-			// no code paths under internal need to be inspected
-			if strings.HasPrefix(relativeBase, "internal") {
-				return nil
-			}
-
-			for _, a := range cfg.IgnoredPaths {
-				if filepath.Join(filepath.SplitList(a)...) == relativeBase {
-					fmt.Printf("Ignoring %s per denylist\n", base)
-					return nil
-				}
-			}
-			metadata, found, err3 := internal.ReadMetadata(base)
-			if err3 != nil {
-				return err3
-			}
-			if !found {
-				return nil
-			}
-			if err = walkFolder(cfg, base, metadata); err != nil {
-				errs = append(errs, err)
+	versions, err := readVersions(versionsPath)
+	if err != nil {
+		return nil, err
+	}
+	// the modules of the repository are only needed by the modules checking cross-module internal imports.
+	repositoryModulePaths := sync.OnceValues(func() ([]string, error) {
+		return internal.RepositoryModulePaths(folder)
+	})
+	return walkModules(folder, cfg, affected, func(base string, _ string, moduleCfg internal.Config, metadata internal.Metadata) ([]internal.Diagnostic, error) {
+		_, version, err := moduleSetOf(versions, base)
+		if err != nil {
+			return nil, err
+		}
+		var modulePaths []string
+		if moduleCfg.CrossModuleInternalImports.Enabled {
+			if modulePaths, err = repositoryModulePaths(); err != nil {
+				return nil, err
 			}
 		}
-		return nil
+		return walkFolder(moduleCfg, base, metadata, version, modulePaths)
 	})
+}
+
+// writeSnapshots writes the API of every module as a JSON snapshot under snapshotsDir, mirroring the module layout.
+func writeSnapshots(folder string, configPath string, snapshotsDir string) error {
+	cfg, err := internal.ReadConfig(configPath)
 	if err != nil {
 		return err
 	}
-	if len(errs) > 0 {
-		return errors.Join(errs...)
-	}
-	return nil
+	_, err = walkModules(folder, cfg, nil, func(base string, relativeBase string, moduleCfg internal.Config, _ internal.Metadata) ([]internal.Diagnostic, error) {
+		result, _, err := internal.ReadModule(moduleCfg, base, apiCache)
+		if err != nil {
+			return nil, err
+		}
+		return nil, internal.WriteSnapshot(filepath.Join(snapshotsDir, relativeBase, internal.SnapshotFileName), result)
+	})
+	return err
 }
 
-func walkFolder(cfg internal.Config, folder string, metadata internal.Metadata) error {
-	result, err := internal.Read(folder, cfg.IgnoredFunctions, cfg.ExcludedFiles)
+// fixSchemas rewrites the config section of the metadata.yaml file of every component
+// with the JSON schema derived from its config struct, if the section is missing or does not match it.
+func fixSchemas(folder string, configPath string) error {
+	cfg, err := internal.ReadConfig(configPath)
 	if err != nil {
 		return err
 	}
-
-	sort.Slice(result.Structs, func(i int, j int) bool {
-		return strings.Compare(result.Structs[i].Name, result.Structs[j].Name) > 0
-	})
-	sort.Strings(result.Values)
-	sort.Slice(result.Functions, func(i int, j int) bool {
-		return strings.Compare(result.Functions[i].Name, result.Functions[j].Name) < 0
-	})
-	fnNames := make([]string, len(result.Functions))
-	for i, fn := range result.Functions {
-		fnNames[i] = fn.Name
-	}
-	if len(result.Structs) == 0 && len(result.Values) == 0 && len(result.Functions) == 0 {
-		// nothing to validate, return
-		return nil
-	}
-
-	var errs []error
-	componentType := metadata.Status.Class
-	isFactoryComponent := componentType == "connector" || componentType == "exporter" || componentType == "extension" || componentType == "processor" || componentType == "receiver"
-
-	if len(cfg.AllowedFunctions) > 0 {
-
-		functionsPresent := map[string]struct{}{}
-		functionsRemaining := make(map[string]struct{}, len(result.Functions))
-		for _, fn := range result.Functions {
-			if !fn.Internal {
-				functionsRemaining[fn.Name] = struct{}{}
-			}
+	_, err = walkModules(folder, cfg, nil, func(base string, _ string, moduleCfg internal.Config, metadata internal.Metadata) ([]internal.Diagnostic, error) {
+		if !internal.IsFactoryComponent(metadata.Status.Class) || (!moduleCfg.JSONSchema.CheckPresent && !moduleCfg.JSONSchema.CheckValid) {
+			return nil, nil
 		}
-		for _, fnDesc := range cfg.AllowedFunctions {
-			if !slices.Contains(fnDesc.Classes, metadata.Status.Class) {
-				continue
-			}
-			// any function
-			if fnDesc.Name == "*" {
-				functionsPresent[""] = struct{}{}
-				functionsRemaining = map[string]struct{}{}
-				break
-			}
-			// no functions at all.
-			if fnDesc.Name == "" {
-				functionsPresent[""] = struct{}{}
-				functionsRemaining = map[string]struct{}{}
-				fnNames = make([]string, 0, len(result.Functions))
-				for _, fn := range result.Functions {
-					if !fn.Internal {
-						fnNames = append(fnNames, fn.Name)
-					}
-				}
-				if len(fnNames) > 0 {
-					errs = append(errs, fmt.Errorf("[%s] no functions must be exported under this module, found %q", folder, strings.Join(fnNames, ",")))
-				}
+		result, _, err := internal.ReadModule(moduleCfg, base, apiCache)
+		if err != nil {
+			return nil, err
+		}
+		var cfgStruct *internal.APIstruct
+		for _, s := range result.Structs {
+			if s.Name == result.ConfigStructName {
+				cfgStruct = &s
 				break
 			}
-
-			for _, fn := range result.Functions {
-				if fn.Name == fnDesc.Name &&
-					slices.Equal(fn.Params, fnDesc.Parameters) &&
-					slices.Equal(fn.ReturnTypes, fnDesc.ReturnTypes) {
-					functionsPresent[fn.Name] = struct{}{}
-					delete(functionsRemaining, fn.Name)
-				}
-			}
 		}
-
-		if len(functionsPresent) == 0 {
-			errs = append(errs, fmt.Errorf("[%s] no function matching configuration found", folder))
+		if cfgStruct == nil {
+			return nil, nil
 		}
-
-		if len(functionsRemaining) > 0 {
-			names := make([]string, 0, len(functionsRemaining))
-			for fnName := range functionsRemaining {
-				names = append(names, fnName)
-			}
-			sort.Strings(names)
-			errs = append(errs, fmt.Errorf("[%s] these functions should not be exported: %q", folder, strings.Join(names, ",")))
+		structDerivedSchema, err := internal.DeriveSchema(*cfgStruct, result, moduleCfg.JSONSchema.TypeMappings)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	if cfg.UnkeyedLiteral.Enabled {
-		for _, s := range result.Structs {
-			if err = checkStructDisallowUnkeyedLiteral(cfg, s, folder); err != nil {
-				errs = append(errs, err)
+		if metadata.Config != nil {
+			configSchema, err := internal.CompileSchema(metadata.Config)
+			if err == nil && internal.CompareJSONSchema(configSchema, structDerivedSchema) == nil {
+				return nil, nil
 			}
 		}
-	}
-
-	if (!cfg.JSONSchema.CheckPresent && !cfg.JSONSchema.CheckValid && !cfg.ComponentAPI && !cfg.ComponentAPIStrict && !cfg.EmbeddedConfigFields.Enabled) || !isFactoryComponent {
-		return errors.Join(errs...)
-	}
-
-	if result.ConfigStructName == "" && cfg.ComponentAPIStrict {
-		errs = append(errs, fmt.Errorf("[%s] cannot find the createDefaultConfig function", folder))
-		return errors.Join(errs...)
-	}
-
-	var cfgStruct *internal.APIstruct
-	allStructs := make(map[string]struct{}, len(result.Structs))
-	structsByName := make(map[string]internal.APIstruct, len(result.Structs))
-	for _, s := range result.Structs {
-		segments := strings.Split(s.Name, ".")
-		name := segments[len(segments)-1]
-		if ast.IsExported(name) {
-			allStructs[s.Name] = struct{}{}
-			structsByName[s.Name] = s
-		}
-		if s.Name == result.ConfigStructName {
-			cfgStruct = &s
+		config, err := internal.SchemaToYAML(structDerivedSchema)
+		if err != nil {
+			return nil, err
 		}
-	}
-	if cfgStruct == nil {
-		if cfg.ComponentAPIStrict {
-			errs = append(errs, fmt.Errorf("[%s] cannot find the config struct", folder))
+		if err = internal.WriteMetadataConfig(base, config); err != nil {
+			return nil, err
 		}
-		return errors.Join(errs...)
-	}
+		fmt.Printf("Updated the JSON schema of %s\n", base)
+		return nil, nil
+	})
+	return err
+}
 
-	if cfg.EmbeddedConfigFields.Enabled {
-		errs = append(errs, checkNoEmbeddedConfigFields(cfg.EmbeddedConfigFields, structsByName, *cfgStruct, folder, map[string]struct{}{})...)
+// diffSnapshots compares the API of every module with its JSON snapshot under snapshotsDir
+// and reports the breaking changes. Modules without a snapshot are skipped.
+// If versionsPath is set, breaking changes are handled according to the policy of the module set
+// of each module, otherwise they are all reported as errors. If affected is set, only the affected modules are compared.
+func diffSnapshots(folder string, configPath string, snapshotsDir string, versionsPath string, affected *internal.AffectedModules) ([]internal.Diagnostic, error) {
+	cfg, err := internal.ReadConfig(configPath)
+	if err != nil {
+		return nil, err
 	}
-
-	if metadata.Config == nil {
-		if cfg.JSONSchema.CheckPresent {
-			errs = append(errs, err)
+	versions, err := readVersions(versionsPath)
+	if err != nil {
+		return nil, err
+	}
+	return walkModules(folder, cfg, affected, func(base string, relativeBase string, moduleCfg internal.Config, _ internal.Metadata) ([]internal.Diagnostic, error) {
+		before, err := internal.ReadSnapshot(filepath.Join(snapshotsDir, relativeBase, internal.SnapshotFileName))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
 		}
-	} else if cfg.JSONSchema.CheckValid {
-		configSchemaBytes, err := json.Marshal(metadata.Config)
 		if err != nil {
-			errs = append(errs, err)
-		} else {
-			configSchema, err := jsonschema.NewCompiler().Compile(configSchemaBytes)
+			return nil, err
+		}
+		policy := internal.PolicyError
+		if versions != nil {
+			moduleSet, version, err := moduleSetOf(versions, base)
 			if err != nil {
-				errs = append(errs, err)
-			} else {
-				var structDerivedSchema *jsonschema.Schema
-				if structDerivedSchema, err = internal.DeriveSchema(*cfgStruct, result.Structs, cfg.JSONSchema.TypeMappings); err != nil {
-					errs = append(errs, err)
-				} else if err := internal.CompareJSONSchema(folder, configSchema, structDerivedSchema); err != nil {
-					errs = append(errs, err)
-					configSchemaBytes, _ := structDerivedSchema.MarshalJSON()
-					rawJSON := map[string]any{}
-					_ = json.Unmarshal(configSchemaBytes, &rawJSON)
-					configSchemaYAML, _ := yaml.Marshal(rawJSON)
-					errs = append(errs, fmt.Errorf("[%s] new JSON schema: %s", folder, string(configSchemaYAML)))
-				}
+				return nil, err
 			}
+			policy = moduleCfg.BreakingChanges.Policy(moduleSet, version)
+		} else if moduleCfg.BreakingChanges.Override != "" {
+			policy = moduleCfg.BreakingChanges.Override
 		}
-	}
-
-	if cfg.ComponentAPIStrict || cfg.ComponentAPI {
-		delete(allStructs, cfgStruct.Name)
-		filterStructs(structsByName, *cfgStruct, allStructs)
-		for k, v := range structsByName {
-			if v.Internal {
-				delete(allStructs, k)
-			}
+		var severity string
+		switch policy {
+		case internal.PolicyIgnore:
+			return nil, nil
+		case internal.PolicyError:
+			severity = internal.SeverityError
+		case internal.PolicyWarn:
+			severity = internal.SeverityWarning
+		default:
+			return nil, fmt.Errorf("[%s] unknown breaking change policy %q", base, policy)
 		}
-		if len(allStructs) > 0 {
-			structNames := make([]string, 0, len(allStructs))
-			for k := range allStructs {
-				structNames = append(structNames, k)
-			}
-			errs = append(errs, fmt.Errorf("[%s] these structs are not part of config and cannot be exported: %s", folder, strings.Join(structNames, ",")))
+		after, _, err := internal.ReadModule(moduleCfg, base, apiCache)
+		if err != nil {
+			return nil, err
 		}
-	}
-
-	return errors.Join(errs...)
-}
-
-func filterStructs(structMap map[string]internal.APIstruct, current internal.APIstruct, allStructs map[string]struct{}) {
-	for _, f := range current.Fields {
-		if s, ok := structMap[f.Type]; ok {
-			delete(allStructs, s.Name)
-			filterStructs(structMap, s, allStructs)
+		var diags []internal.Diagnostic
+		for _, change := range internal.BreakingChanges(before, after) {
+			d := internal.NewDiagnostic(internal.RuleBreakingChanges, base, change.Pos, "breaking change: %s", change.Message)
+			d.Severity = severity
+			diags = append(diags, d)
 		}
-	}
+		return diags, nil
+	})
 }
 
-// checkNoEmbeddedConfigFields reports the embedded (anonymous) fields of the config struct and of
-// every struct reachable from it.
-func checkNoEmbeddedConfigFields(cfg internal.EmbeddedConfigFields, structMap map[string]internal.APIstruct, current internal.APIstruct, folder string, visited map[string]struct{}) []error {
-	if _, seen := visited[current.Name]; seen {
-		return nil
+// applyBaseline removes the known violations of the baseline file from the diagnostics of the modules under folder.
+// If write is set, the baseline file is first rewritten with all the current violations. If prune is set,
+// the known violations that no longer occur are removed from it. Neither can be set if only some modules were checked,
+// as the violations of the other modules would be lost.
+func applyBaseline(folder string, baselinePath string, write bool, prune bool, partial bool, diags []internal.Diagnostic) ([]internal.Diagnostic, error) {
+	if (write || prune) && partial {
+		return nil, errors.New("the baseline cannot be written or pruned when only the affected modules are checked")
 	}
-	visited[current.Name] = struct{}{}
-
-	var embedded []string
-	for _, f := range current.Fields {
-		if f.Name == "" && !slices.Contains(cfg.IgnoredTypes, f.Type) {
-			embedded = append(embedded, f.Type)
+	if write {
+		baseline := internal.NewBaseline(folder, diags)
+		if err := internal.WriteBaseline(baselinePath, baseline); err != nil {
+			return nil, err
 		}
+		fmt.Fprintf(os.Stderr, "Wrote %d known violations to %s\n", len(baseline.Violations), baselinePath)
 	}
-	var errs []error
-	if len(embedded) > 0 {
-		errs = append(errs, fmt.Errorf("[%s] config struct %q must not have embedded fields, found %q", folder, current.Name, strings.Join(embedded, ",")))
+	baseline, err := internal.ReadBaseline(baselinePath)
+	if err != nil {
+		return nil, err
 	}
-	for _, f := range current.Fields {
-		if s, ok := structMap[f.Type]; ok {
-			errs = append(errs, checkNoEmbeddedConfigFields(cfg, structMap, s, folder, visited)...)
+	remaining, stale := baseline.Filter(folder, diags)
+	switch {
+	case len(stale.Violations) == 0 || partial:
+	case prune:
+		if err = internal.WriteBaseline(baselinePath, baseline.Without(stale)); err != nil {
+			return nil, err
 		}
+		fmt.Fprintf(os.Stderr, "Removed %d fixed violations from %s\n", len(stale.Violations), baselinePath)
+	default:
+		fmt.Fprintf(os.Stderr, "%d known violations of %s no longer occur, remove them with -prune-baseline\n", len(stale.Violations), baselinePath)
 	}
-	return errs
+	return remaining, nil
 }
 
-func checkStructDisallowUnkeyedLiteral(cfg internal.Config, s internal.APIstruct, folder string) error {
-	if s.Internal {
-		return nil
+// affectedModules returns the modules affected by the changes since the merge base of baseRef and HEAD,
+// or by the files listed in the file at changedFilesPath. It returns nil if neither is set.
+func affectedModules(folder string, baseRef string, changedFilesPath string) (*internal.AffectedModules, error) {
+	var files []string
+	var err error
+	switch {
+	case baseRef != "" && changedFilesPath != "":
+		return nil, errors.New("only one of -base-ref and -changed-files can be set")
+	case baseRef != "":
+		files, err = internal.ChangedFiles(folder, baseRef)
+	case changedFilesPath != "":
+		files, err = internal.ReadChangedFiles(changedFilesPath)
+	default:
+		return nil, nil
 	}
-	if !unicode.IsUpper(rune(s.Name[0])) {
-		return nil
+	if err != nil {
+		return nil, err
 	}
-	if len(s.Fields) > cfg.UnkeyedLiteral.Limit {
-		return nil
+	affected, err := internal.FindAffectedModules(folder, files)
+	if err != nil {
+		return nil, err
 	}
-	if len(s.Fields) == 0 {
-		return nil
+	return &affected, nil
+}
+
+// readVersions reads the multimod versions.yaml file at versionsPath, if it is set.
+func readVersions(versionsPath string) (*internal.Versions, error) {
+	if versionsPath == "" {
+		return nil, nil
+	}
+	versions, err := internal.ReadVersions(versionsPath)
+	if err != nil {
+		return nil, err
 	}
+	return &versions, nil
+}
 
-	for _, f := range s.Fields {
-		if len(f.Name) == 0 {
-			if !unicode.IsUpper(rune(f.Type[0])) {
+// moduleSetOf returns the name and version of the module set of the module in folder.
+// They are empty if versions are not set or if the module is not part of any module set.
+func moduleSetOf(versions *internal.Versions, folder string) (string, string, error) {
+	if versions == nil {
+		return "", "", nil
+	}
+	modulePath, err := internal.ReadModulePath(folder)
+	if err != nil {
+		return "", "", err
+	}
+	moduleSet, version, _ := versions.ModuleSetOf(modulePath)
+	return moduleSet, version, nil
+}
+
+// walkModules calls fn for every module under folder that has a metadata.yaml file and is not ignored,
+// with the configuration of the module, and collects the diagnostics and joins the errors it returns.
+// If affected is set, modules that are not affected are skipped.
+// Up to parallelism modules are processed concurrently, and the results are collected in the order modules are found.
+func walkModules(folder string, cfg internal.Config, affected *internal.AffectedModules, fn func(base string, relativeBase string, cfg internal.Config, metadata internal.Metadata) ([]internal.Diagnostic, error)) ([]internal.Diagnostic, error) {
+	type module struct {
+		base         string
+		relativeBase string
+		cfg          internal.Config
+		metadata     internal.Metadata
+		diags        []internal.Diagnostic
+		err          error
+	}
+	var modules []*module
+	err := filepath.Walk(folder, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Name() == "go.mod" {
+			base := filepath.Dir(path)
+			relativeBase, err2 := filepath.Rel(folder, base)
+			if err2 != nil {
+				return err2
+			}
+			// no code paths under internal need to be inspected
+			if strings.HasPrefix(relativeBase, "internal") {
 				return nil
 			}
-		} else {
-			if !unicode.IsUpper(rune(f.Name[0])) {
+
+			for _, a := range cfg.IgnoredPaths {
+				if filepath.Join(filepath.SplitList(a)...) == relativeBase {
+					fmt.Fprintf(os.Stderr, "Ignoring %s per denylist\n", base)
+					return nil
+				}
+			}
+			if affected != nil {
+				ok, err := affected.Includes(base, relativeBase)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return nil
+				}
+			}
+			metadata, found, err3 := internal.ReadMetadata(base)
+			if err3 != nil {
+				return err3
+			}
+			if !found {
 				return nil
 			}
+			moduleCfg, err := cfg.ForModule(base, metadata)
+			modules = append(modules, &module{base: base, relativeBase: relativeBase, cfg: moduleCfg, metadata: metadata, err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, max(parallelism, 1))
+	for _, m := range modules {
+		if m.err != nil {
+			continue
 		}
+		wg.Go(func() {
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			m.diags, m.err = fn(m.base, m.relativeBase, m.cfg, m.metadata)
+		})
+	}
+	wg.Wait()
+
+	var diags []internal.Diagnostic
+	var errs []error
+	for _, m := range modules {
+		if m.err != nil {
+			errs = append(errs, m.err)
+		}
+		diags = append(diags, m.diags...)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return diags, nil
+}
+
+// walkFolder checks the module in folder. version is the version of its module set, if known,
+// and modulePaths are the paths of the modules of its repository, if cross-module internal imports are checked.
+func walkFolder(cfg internal.Config, folder string, metadata internal.Metadata, version string, modulePaths []string) ([]internal.Diagnostic, error) {
+	result, platformSpecific, err := internal.ReadModule(cfg, folder, apiCache)
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("[%s] struct %q does not prevent unkeyed literal initialization", folder, s.Name)
+	return internal.CheckModule(cfg, folder, metadata, version, modulePaths, result, platformSpecific)
 }
@@ -6,10 +6,15 @@
 package main
 
 import (
+	"io/fs"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/build-tools/checkapi/internal"
 )
 
 func TestValidPkg(t *testing.T) {
@@ -30,7 +35,8 @@ func TestPkgPkg(t *testing.T) {
 func TestPkgPkgNoFunctions(t *testing.T) {
 	t.Chdir("internal")
 	err := run("pkg", filepath.Join("pkg", "config_no_functions.yaml"))
-	require.EqualError(t, err, `[pkg/pkg] no functions must be exported under this module, found "OtherFunc,SomeFunc"`)
+	require.EqualError(t, err, `[pkg/pkg] no function must be exported under this module, found "OtherFunc"
+[pkg/pkg] no function must be exported under this module, found "SomeFunc"`)
 }
 
 func TestPkgPkgSpecificFunctionsAllowed(t *testing.T) {
@@ -42,7 +48,7 @@ func TestPkgPkgSpecificFunctionsAllowed(t *testing.T) {
 func TestPkgPkgOnlyOneAllowedFunctionAllowed(t *testing.T) {
 	t.Chdir("internal")
 	err := run("pkg", filepath.Join("pkg", "config_only_one_allowed.yaml"))
-	require.EqualError(t, err, `[pkg/pkg] these functions should not be exported: "OtherFunc"`)
+	require.EqualError(t, err, `[pkg/pkg] function "OtherFunc" should not be exported`)
 }
 
 func TestPkgPkgAllFunctionsAllowed(t *testing.T) {
@@ -51,18 +57,33 @@ func TestPkgPkgAllFunctionsAllowed(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPkgPkgPatternFunctionsAllowed(t *testing.T) {
+	t.Chdir("internal")
+	err := run("pkg", filepath.Join("pkg", "config_patterns.yaml"))
+	require.NoError(t, err)
+}
+
 func TestPkgPkgMissingFunction(t *testing.T) {
 	t.Chdir("internal")
 	err := run("pkg", filepath.Join("pkg", "config_missing_function.yaml"))
 	require.EqualError(t, err, `[pkg/pkg] no function matching configuration found
-[pkg/pkg] these functions should not be exported: "OtherFunc,SomeFunc"`)
+[pkg/pkg] function "OtherFunc" should not be exported
+[pkg/pkg] function "SomeFunc" should not be exported`)
 }
 
 func TestPkgPkgWrongReturnType(t *testing.T) {
 	t.Chdir("internal")
 	err := run("pkg", filepath.Join("pkg", "config_wrong_return_type.yaml"))
 	require.EqualError(t, err, `[pkg/pkg] no function matching configuration found
-[pkg/pkg] these functions should not be exported: "OtherFunc,SomeFunc"`)
+[pkg/pkg] function "OtherFunc" should not be exported
+[pkg/pkg] function "SomeFunc" should not be exported`)
+}
+
+func TestPkgPkgAllowedTypes(t *testing.T) {
+	t.Chdir("internal")
+	err := run("pkg", filepath.Join("pkg", "config_allowed_types.yaml"))
+	require.EqualError(t, err, `[pkg/pkg] type "SomeAlias" should not be exported
+[pkg/pkg] type "SomeType" should not be exported`)
 }
 
 func TestAltConfig(t *testing.T) {
@@ -81,11 +102,29 @@ func TestUnkeyedPkg(t *testing.T) {
 	require.EqualError(t, err, `[receiver/unkeyedreceiver] struct "UnkeyedConfig" does not prevent unkeyed literal initialization`)
 }
 
+func TestUnkeyedPkgDiagnostics(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "unkeyedpkg"))
+	diags, err := check(".", filepath.Join("..", "..", "config.yaml"), "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []internal.Diagnostic{{
+		Rule:     internal.RuleUnkeyedLiteral,
+		Severity: internal.SeverityError,
+		Module:   filepath.Join("receiver", "unkeyedreceiver"),
+		Position: internal.Position{File: filepath.Join("receiver", "unkeyedreceiver", "code_test.go"), Line: 20, Column: 6},
+		Message:  `struct "UnkeyedConfig" does not prevent unkeyed literal initialization`,
+	}}, diags)
+}
+
 func TestMissingConfigFile(t *testing.T) {
 	err := run(filepath.Join("internal", "unkeyedpkg"), "badconfig.yaml")
 	require.EqualError(t, err, `open badconfig.yaml: no such file or directory`)
 }
 
+func TestMissingFolder(t *testing.T) {
+	err := run(filepath.Join("internal", "missing"), "config.yaml")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestComponentConfig(t *testing.T) {
 	t.Chdir(filepath.Join("internal", "config", "receiver", "configreceiver"))
 	err := run(".", filepath.Join("..", "..", "config.yaml"))
@@ -98,20 +137,63 @@ func TestComponentCallConfig(t *testing.T) {
 	require.NoError(t, err, "all config structs are valid")
 }
 
+func TestFixSchemas(t *testing.T) {
+	configPath, err := filepath.Abs(filepath.Join("internal", "config", "config.yaml"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS(filepath.Join("internal", "config", "receiver", "configreceiver"))))
+	metadataPath := filepath.Join(dir, "metadata.yaml")
+	require.NoError(t, os.WriteFile(metadataPath, []byte(`# the receiver used by tests
+type: config
+
+status:
+  class: receiver # a factory component
+
+# derived from the Config struct
+config:
+  properties:
+    foo:
+      type: string
+  type: object
+`), 0o600))
+	t.Chdir(dir)
+	require.Error(t, run(".", configPath))
+
+	require.NoError(t, fixSchemas(".", configPath))
+	require.NoError(t, run(".", configPath))
+	metadata, err := os.ReadFile(metadataPath)
+	require.NoError(t, err)
+	require.Contains(t, string(metadata), "# the receiver used by tests\n")
+	require.Contains(t, string(metadata), "class: receiver # a factory component\n")
+	require.Contains(t, string(metadata), "# derived from the Config struct\nconfig:\n")
+	require.Contains(t, string(metadata), "    my_special_embedded_field:\n      type: string\n")
+
+	// schemas that already match are left untouched.
+	info, err := os.Stat(metadataPath)
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(metadataPath, info.ModTime(), info.ModTime().Add(-time.Hour)))
+	require.NoError(t, fixSchemas(".", configPath))
+	unchanged, err := os.Stat(metadataPath)
+	require.NoError(t, err)
+	require.Equal(t, info.ModTime().Add(-time.Hour), unchanged.ModTime())
+}
+
 func TestEmbeddedConfigFields(t *testing.T) {
 	for _, tt := range []struct{ name, folder, config, expectedErr string }{
 		{
 			name:   "embedded fields at every depth",
 			folder: filepath.Join("receiver", "configcallreceiver"),
 			config: "config_embedded.yaml",
-			expectedErr: `[.] config struct "Config" must not have embedded fields, found "Embedded,EmbeddedPtr"
+			expectedErr: `[.] config struct "Config" must not have embedded fields, found "Embedded"
+[.] config struct "Config" must not have embedded fields, found "EmbeddedPtr"
 [.] config struct "SubConfig2" must not have embedded fields, found "DeepEmbedded"`,
 		},
 		{
-			name:        "embedded type from another package",
-			folder:      filepath.Join("receiver", "configreceiver"),
-			config:      "config_embedded.yaml",
-			expectedErr: `[.] config struct "Config" must not have embedded fields, found "emb.Config,EmbeddedPtr"`,
+			name:   "embedded type from another package",
+			folder: filepath.Join("receiver", "configreceiver"),
+			config: "config_embedded.yaml",
+			expectedErr: `[.] config struct "Config" must not have embedded fields, found "emb.Config"
+[.] config struct "Config" must not have embedded fields, found "EmbeddedPtr"`,
 		},
 		{
 			name:        "ignored types are allowed",
@@ -134,8 +216,414 @@ func TestEmbeddedConfigFields(t *testing.T) {
 	}
 }
 
+func TestConfigConventions(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "config", "receiver", "conventionsreceiver"))
+	err := run(".", filepath.Join("..", "..", "config_conventions.yaml"))
+	require.EqualError(t, err, `[.] config struct "Config" must have a Validate() error method
+[.] field "Untagged" of config struct "Config" must have a mapstructure tag
+[.] mapstructure key "timeOut" of config struct "Config" must be lower_snake_case
+[.] mapstructure key "endpoint" of config struct "Config" is not unique once squashed fields are flattened
+[.] field "TLS" of config struct "ClientConfig" must have a mapstructure tag`)
+}
+
+func TestConfigDefaults(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "config", "receiver", "defaultsreceiver"))
+	err := run(".", filepath.Join("..", "..", "config_defaults.yaml"))
+	require.EqualError(t, err, `[.] default value 10 of field "client.max_idle" differs from its default 100 in the schema
+[.] default value true of field "enabled" differs from its default false in the schema
+[.] default value 1m0s of field "interval" differs from its default 30s in the schema
+[.] default value medium of field "mode" is not one of its enum values [fast slow]
+[.] default value 3 of field "retries" does not match its type ["string"]`)
+}
+
 func TestComponentConfigBadStruct(t *testing.T) {
 	t.Chdir(filepath.Join("internal", "config", "receiver", "badconfigreceiver"))
 	err := run(".", filepath.Join("..", "..", "config.yaml"))
-	require.EqualError(t, err, "[.] these structs are not part of config and cannot be exported: ExtraStruct")
+	require.EqualError(t, err, "[.] struct \"ExtraStruct\" is not part of config and cannot be exported")
+}
+
+func TestModuleConfigOverrides(t *testing.T) {
+	configPath, err := filepath.Abs(filepath.Join("internal", "pkg", "config_only_one_allowed.yaml"))
+	require.NoError(t, err)
+	for _, tt := range []struct {
+		name             string
+		metadataOverride string
+		moduleConfig     string
+		expectedErr      string
+	}{
+		{
+			name:        "no overrides",
+			expectedErr: `[pkg] function "OtherFunc" should not be exported`,
+		},
+		{
+			name:         "rule disabled in .checkapi.yaml",
+			moduleConfig: "rules:\n  allowed_functions: false\n",
+		},
+		{
+			name:             "function ignored in metadata.yaml",
+			metadataOverride: "checkapi:\n  ignored_functions: [\"^Other\"]\n",
+		},
+		{
+			name:             "overrides in both files",
+			metadataOverride: "checkapi:\n  ignored_functions: [\"^Other\"]\n",
+			moduleConfig:     "rules:\n  allowed_functions: false\n",
+			expectedErr:      "pkg: configuration overrides must be set in either metadata.yaml or .checkapi.yaml, not both",
+		},
+		{
+			name:         "unknown rule",
+			moduleConfig: "rules:\n  allowed_function: false\n",
+			expectedErr:  `[pkg] invalid configuration overrides: unknown rules ["allowed_function"], must be one of ["allowed_functions" "allowed_types" "unkeyed_literal_initialization" "component_api" "json_schema" "embedded_config_fields" "breaking_changes" "deprecations" "exported_variables" "unexported_return_types" "unexported_parameter_types" "platform_specific_api" "config_conventions" "cross_module_internal_imports"]`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.CopyFS(filepath.Join(dir, "pkg"), os.DirFS(filepath.Join("internal", "pkg", "pkg"))))
+			if tt.metadataOverride != "" {
+				metadata, err := os.ReadFile(filepath.Join(dir, "pkg", "metadata.yaml"))
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "metadata.yaml"), append(metadata, tt.metadataOverride...), 0o600))
+			}
+			if tt.moduleConfig != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", internal.ModuleConfigFileName), []byte(tt.moduleConfig), 0o600))
+			}
+			t.Chdir(dir)
+			err := run(".", configPath)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		versions    string
+		expectedErr string
+	}{
+		{
+			name:        "without versions",
+			expectedErr: `[pkg] UnversionedFunc is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice`,
+		},
+		{
+			name:     "with versions",
+			versions: "versions.yaml",
+			expectedErr: `[pkg] OldFunc was deprecated in v1.1.0, more than 2 minor releases before v1.5.0, and must be removed
+[pkg] UnversionedFunc is deprecated without the version it was deprecated in, add "Deprecated since vX.Y.Z" to its notice
+[pkg] SomeStruct.OldField was deprecated in v0.9.0, more than 2 minor releases before v1.5.0, and must be removed
+[pkg] SomeStruct.OldMethod was deprecated in v1.2.0, more than 2 minor releases before v1.5.0, and must be removed`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(filepath.Join("internal", "deprecatedpkg"))
+			diags, err := check("pkg", "config.yaml", tt.versions, nil)
+			require.NoError(t, err)
+			for _, d := range diags {
+				require.Equal(t, internal.RuleDeprecations, d.Rule)
+			}
+			require.EqualError(t, internal.DiagnosticsError(diags), tt.expectedErr)
+		})
+	}
+}
+
+func TestCrossModuleInternalImports(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "crossmodulepkg"))
+	diags, err := check(".", "config.yaml", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []internal.Diagnostic{
+		{
+			Rule:     internal.RuleCrossModuleImports,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "pkg.go"), Line: 8, Column: 2},
+			Message:  `internal package "foo/bar/crossmodulepkg/internal/shared" of module "foo/bar/crossmodulepkg" must not be imported from another module`,
+		},
+	}, diags)
+}
+
+func TestExportedPkg(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "exportedpkg"))
+	diags, err := check("pkg", "config.yaml", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []internal.Diagnostic{
+		{
+			Rule:     internal.RuleExportedVariables,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "pkg.go"), Line: 16, Column: 5},
+			Message:  `variable "Registry" of type "map[string]string" must not be exported, as it can be modified by any importer`,
+		},
+		{
+			Rule:     internal.RuleUnexportedParameters,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "pkg.go"), Line: 26, Column: 6},
+			Message:  `exported function "Apply" takes unexported types "settings"`,
+		},
+		{
+			Rule:     internal.RuleUnexportedReturns,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "pkg.go"), Line: 21, Column: 6},
+			Message:  `exported function "NewSettings" returns unexported types "settings"`,
+		},
+	}, diags)
+}
+
+func TestPlatformPkg(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "platformpkg"))
+	diags, err := check("pkg", "config.yaml", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []internal.Diagnostic{
+		{
+			Rule:     internal.RulePlatformSpecificAPI,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "helper_unix.go"), Line: 9, Column: 6},
+			Message:  `function "UnixHelper" is only declared on linux/amd64`,
+		},
+		{
+			Rule:     internal.RulePlatformSpecificAPI,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "helper_windows.go"), Line: 7, Column: 6},
+			Message:  `function "WindowsHelper" is only declared on windows/amd64`,
+		},
+		{
+			Rule:     internal.RulePlatformSpecificAPI,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "helper_unix.go"), Line: 12, Column: 6},
+			Message:  `struct "UnixOnly" is only declared on linux/amd64`,
+		},
+		{
+			Rule:     internal.RuleAllowedFunctions,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "helper_unix.go"), Line: 9, Column: 6},
+			Message:  `function "UnixHelper" should not be exported`,
+		},
+		{
+			Rule:     internal.RuleAllowedFunctions,
+			Severity: internal.SeverityError,
+			Module:   "pkg",
+			Position: internal.Position{File: filepath.Join("pkg", "helper_windows.go"), Line: 7, Column: 6},
+			Message:  `function "WindowsHelper" should not be exported`,
+		},
+	}, diags)
+}
+
+func TestAffectedModules(t *testing.T) {
+	configPath, err := filepath.Abs(filepath.Join("internal", "pkg", "config_only_one_allowed.yaml"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	for _, module := range []string{"changed", "unchanged"} {
+		require.NoError(t, os.CopyFS(filepath.Join(dir, module), os.DirFS(filepath.Join("internal", "pkg", "pkg"))))
+	}
+	changedFilesPath := filepath.Join(t.TempDir(), "changed.txt")
+	require.NoError(t, os.WriteFile(changedFilesPath, []byte("changed/pkg.go\n"), 0o600))
+
+	affected, err := affectedModules(dir, "", changedFilesPath)
+	require.NoError(t, err)
+	t.Chdir(dir)
+	diags, err := check(".", configPath, "", affected)
+	require.NoError(t, err)
+	require.EqualError(t, internal.DiagnosticsError(diags), `[changed] function "OtherFunc" should not be exported`)
+
+	_, err = affectedModules(dir, "main", changedFilesPath)
+	require.EqualError(t, err, "only one of -base-ref and -changed-files can be set")
+}
+
+func TestParallelism(t *testing.T) {
+	t.Chdir(filepath.Join("internal", "config"))
+	defer func(p int) { parallelism = p }(parallelism)
+	parallelism = 1
+	sequential, err := check(".", "config.yaml", "", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, sequential)
+	parallelism = 4
+	parallel, err := check(".", "config.yaml", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, sequential, parallel)
+}
+
+func TestAPICache(t *testing.T) {
+	configPath, err := filepath.Abs(filepath.Join("internal", "pkg", "config_only_one_allowed.yaml"))
+	require.NoError(t, err)
+	cacheDir := t.TempDir()
+	defer func() { apiCache = nil }()
+	apiCache = internal.NewCache(cacheDir)
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(filepath.Join(dir, "pkg"), os.DirFS(filepath.Join("internal", "pkg", "pkg"))))
+	t.Chdir(dir)
+
+	first, err := check(".", configPath, "", nil)
+	require.NoError(t, err)
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	second, err := check(".", configPath, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	require.NoError(t, os.WriteFile(filepath.Join("pkg", "more.go"), []byte("package pkg\n\n// MoreFunc is a test function.\nfunc MoreFunc() {}\n"), 0o600))
+	third, err := check(".", configPath, "", nil)
+	require.NoError(t, err)
+	require.EqualError(t, internal.DiagnosticsError(third), `[pkg] function "MoreFunc" should not be exported
+[pkg] function "OtherFunc" should not be exported`)
+	entries, err = os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestBaseline(t *testing.T) {
+	configPath, err := filepath.Abs(filepath.Join("internal", "pkg", "config_only_one_allowed.yaml"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(filepath.Join(dir, "pkg"), os.DirFS(filepath.Join("internal", "pkg", "pkg"))))
+	t.Chdir(dir)
+	baselinePath := filepath.Join(dir, "baseline.yaml")
+
+	diags, err := check(".", configPath, "", nil)
+	require.NoError(t, err)
+	_, err = applyBaseline(".", baselinePath, false, false, false, diags)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = applyBaseline(".", baselinePath, true, false, true, diags)
+	require.EqualError(t, err, "the baseline cannot be written or pruned when only the affected modules are checked")
+
+	remaining, err := applyBaseline(".", baselinePath, true, false, false, diags)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+	baseline, err := internal.ReadBaseline(baselinePath)
+	require.NoError(t, err)
+	otherFunc := internal.BaselineEntry{Rule: internal.RuleAllowedFunctions, Module: "pkg", Message: `function "OtherFunc" should not be exported`}
+	require.Equal(t, []internal.BaselineEntry{otherFunc}, baseline.Violations)
+
+	require.NoError(t, os.WriteFile(filepath.Join("pkg", "more.go"), []byte("package pkg\n\n// MoreFunc is a test function.\nfunc MoreFunc() {}\n"), 0o600))
+	diags, err = check(".", configPath, "", nil)
+	require.NoError(t, err)
+	remaining, err = applyBaseline(".", baselinePath, false, true, false, diags)
+	require.NoError(t, err)
+	require.EqualError(t, internal.DiagnosticsError(remaining), `[pkg] function "MoreFunc" should not be exported`, "only the new violation is reported")
+	baseline, err = internal.ReadBaseline(baselinePath)
+	require.NoError(t, err)
+	require.Equal(t, []internal.BaselineEntry{otherFunc}, baseline.Violations, "the known violation is kept")
+
+	_, err = applyBaseline(".", baselinePath, true, false, false, diags)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(filepath.Join("pkg", "more.go")))
+	diags, err = check(".", configPath, "", nil)
+	require.NoError(t, err)
+	remaining, err = applyBaseline(".", baselinePath, false, true, false, diags)
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+	baseline, err = internal.ReadBaseline(baselinePath)
+	require.NoError(t, err)
+	require.Equal(t, []internal.BaselineEntry{otherFunc}, baseline.Violations, "the fixed violation is pruned")
+}
+
+func TestWriteSnapshots(t *testing.T) {
+	t.Chdir("internal")
+	dir := t.TempDir()
+	require.NoError(t, writeSnapshots("pkg", filepath.Join("pkg", "config_allowed.yaml"), dir))
+	snapshot, err := internal.ReadSnapshot(filepath.Join(dir, "pkg", internal.SnapshotFileName))
+	require.NoError(t, err)
+	require.Len(t, snapshot.Structs, 1)
+	require.Equal(t, "SomeStruct", snapshot.Structs[0].Name)
+	require.Len(t, snapshot.Functions, 2)
+	require.Equal(t, "OtherFunc", snapshot.Functions[0].Name)
+	require.Equal(t, "SomeFunc", snapshot.Functions[1].Name)
+	require.Len(t, snapshot.Interfaces, 1)
+	require.Equal(t, "SomeInterface", snapshot.Interfaces[0].Name)
+	require.Equal(t, []string{"fmt.Stringer"}, snapshot.Interfaces[0].Embedded)
+	require.Equal(t, []internal.APItype{{Name: "SomeAlias", Type: "SomeStruct", Alias: true}, {Name: "SomeType", Type: "string"}}, snapshot.Types)
+	require.Equal(t, map[string][]internal.Function{
+		"SomeStruct": {{Name: "Describe", Receiver: "SomeStruct", Params: []string{"string"}, ReturnTypes: []string{"string"}}},
+	}, snapshot.Methods)
+
+	diags, err := diffSnapshots("pkg", filepath.Join("pkg", "config_allowed.yaml"), dir, "", nil)
+	require.NoError(t, err)
+	require.Empty(t, diags)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Chdir("internal")
+	diags, err := diffSnapshots("pkg", filepath.Join("pkg", "config_allowed.yaml"), filepath.Join("pkg", "snapshot"), "", nil)
+	require.NoError(t, err)
+	require.Len(t, diags, 8)
+	require.Equal(t, internal.Diagnostic{
+		Rule:     internal.RuleBreakingChanges,
+		Severity: internal.SeverityError,
+		Module:   filepath.Join("pkg", "pkg"),
+		Position: internal.Position{File: filepath.Join("pkg", "pkg", "pkg.go"), Line: 15, Column: 6},
+		Message:  `breaking change: function "SomeFunc" changed from "func(string,int) bool" to "func(string) bool"`,
+	}, diags[6])
+	require.EqualError(t, internal.DiagnosticsError(diags), `[pkg/pkg] breaking change: value "RemovedValue" removed
+[pkg/pkg] breaking change: field "OneField" of struct "SomeStruct" changed type from "int" to "string"
+[pkg/pkg] breaking change: field "RemovedField" of struct "SomeStruct" removed
+[pkg/pkg] breaking change: method "RemovedMethod" of interface "SomeInterface" removed
+[pkg/pkg] breaking change: type "SomeType" changed from "int" to "string"
+[pkg/pkg] breaking change: function "RemovedFunc" removed
+[pkg/pkg] breaking change: function "SomeFunc" changed from "func(string,int) bool" to "func(string) bool"
+[pkg/pkg] breaking change: method "RemovedMethod" of type "SomeStruct" removed`)
+}
+
+func TestDiffSnapshotsMissingSnapshot(t *testing.T) {
+	t.Chdir("internal")
+	diags, err := diffSnapshots("pkg", filepath.Join("pkg", "config_allowed.yaml"), t.TempDir(), "", nil)
+	require.NoError(t, err)
+	require.Empty(t, diags)
+}
+
+func TestDiffSnapshotsVersions(t *testing.T) {
+	for _, tt := range []struct {
+		name, config, versions, expectedErr string
+		expectedWarnings                    int
+	}{
+		{
+			name:     "stable module set",
+			config:   "config_allowed.yaml",
+			versions: "versions_stable.yaml",
+			expectedErr: `[pkg/pkg] breaking change: value "RemovedValue" removed
+[pkg/pkg] breaking change: field "OneField" of struct "SomeStruct" changed type from "int" to "string"
+[pkg/pkg] breaking change: field "RemovedField" of struct "SomeStruct" removed
+[pkg/pkg] breaking change: method "RemovedMethod" of interface "SomeInterface" removed
+[pkg/pkg] breaking change: type "SomeType" changed from "int" to "string"
+[pkg/pkg] breaking change: function "RemovedFunc" removed
+[pkg/pkg] breaking change: function "SomeFunc" changed from "func(string,int) bool" to "func(string) bool"
+[pkg/pkg] breaking change: method "RemovedMethod" of type "SomeStruct" removed`,
+		},
+		{
+			name:             "unstable module set only warns",
+			config:           "config_allowed.yaml",
+			versions:         "versions_unstable.yaml",
+			expectedWarnings: 8,
+		},
+		{
+			name:     "module set policy override",
+			config:   "config_breaking_changes.yaml",
+			versions: "versions_stable.yaml",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir("internal")
+			diags, err := diffSnapshots("pkg", filepath.Join("pkg", tt.config), filepath.Join("pkg", "snapshot"), filepath.Join("pkg", tt.versions), nil)
+			require.NoError(t, err)
+			warnings := 0
+			for _, d := range diags {
+				if d.Severity == internal.SeverityWarning {
+					warnings++
+				}
+			}
+			require.Equal(t, tt.expectedWarnings, warnings)
+			err = internal.DiagnosticsError(diags)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
 }
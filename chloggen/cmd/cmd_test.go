@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -46,7 +47,7 @@ func enhancementEntry() *chlog.Entry {
 		ChangeType: chlog.Enhancement,
 		Component:  "receiver/foo",
 		Note:       "Add some bar",
-		Issues:     []int{12345},
+		Issues:     []chlog.Issue{"12345"},
 	}
 }
 
@@ -55,7 +56,7 @@ func bugFixEntry() *chlog.Entry {
 		ChangeType: chlog.BugFix,
 		Component:  "testbed",
 		Note:       "Fix blah",
-		Issues:     []int{12346, 12347},
+		Issues:     []chlog.Issue{"12346", "12347"},
 	}
 }
 
@@ -64,7 +65,7 @@ func deprecationEntry() *chlog.Entry {
 		ChangeType: chlog.Deprecation,
 		Component:  "exporter/old",
 		Note:       "Deprecate old",
-		Issues:     []int{12348},
+		Issues:     []chlog.Issue{"12348"},
 	}
 }
 
@@ -73,7 +74,7 @@ func newComponentEntry() *chlog.Entry {
 		ChangeType: chlog.NewComponent,
 		Component:  "exporter/new",
 		Note:       "Add new exporter ...",
-		Issues:     []int{12349},
+		Issues:     []chlog.Issue{"12349"},
 	}
 }
 
@@ -82,7 +83,7 @@ func breakingEntry() *chlog.Entry {
 		ChangeType: chlog.Breaking,
 		Component:  "processor/oops",
 		Note:       "Change behavior when ...",
-		Issues:     []int{12350},
+		Issues:     []chlog.Issue{"12350"},
 	}
 }
 
@@ -93,7 +94,7 @@ func entryWithSubtext() *chlog.Entry {
 		ChangeType: chlog.Breaking,
 		Component:  "processor/oops",
 		Note:       "Change behavior when ...",
-		Issues:     []int{12350},
+		Issues:     []chlog.Issue{"12350"},
 		SubText:    strings.Join(lines, "\n"),
 	}
 }
@@ -108,7 +109,7 @@ func entryForChangelogs(changeType string, issue int, keys ...string) *chlog.Ent
 		ChangeType: changeType,
 		Component:  "receiver/foo",
 		Note:       fmt.Sprintf("Some change relevant to [%s]", keyStr),
-		Issues:     []int{issue},
+		Issues:     []chlog.Issue{chlog.Issue(strconv.Itoa(issue))},
 	}
 }
 
@@ -142,7 +143,12 @@ func setupTestDir(t *testing.T, entries []*chlog.Entry) {
 }
 
 func runCobra(t *testing.T, args ...string) (string, error) {
+	return runCobraWithInput(t, "", args...)
+}
+
+func runCobraWithInput(t *testing.T, input string, args ...string) (string, error) {
 	cmd := rootCmd()
+	cmd.SetIn(strings.NewReader(input))
 
 	outBytes := bytes.NewBufferString("")
 	cmd.SetOut(outBytes)
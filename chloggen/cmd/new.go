@@ -15,21 +15,39 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
+)
+
+var (
+	filename    string
+	interactive bool
+	newEntry    chlog.Entry
+	newIssues   []string
 )
 
-var filename string
+// entryFlags are the flags setting the fields of the new entry.
+var entryFlags = []string{"change-logs", "change-type", "component", "note", "issues", "subtext"}
 
 func newCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "new",
 		Short: "Creates new change file",
+		Long: `Creates a new change file. Without any entry flag, the entry template is copied to the file to be edited.
+With entry flags or --interactive, the entry is validated and written with the given fields,
+and --interactive prompts for the fields that are not set by flags.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			sanitizedName := filepath.Base(cleanFileName(filename))
 			if sanitizedName == "." || sanitizedName == ".." || sanitizedName == "" {
@@ -47,6 +65,10 @@ func newCmd() *cobra.Command {
 				pathWithExt = path + ".yaml"
 			}
 
+			if interactive || slices.ContainsFunc(entryFlags, cmd.Flags().Changed) {
+				return writeEntry(cmd, pathWithExt)
+			}
+
 			templateFile, err := os.Open(filepath.Clean(globalCfg.TemplateYAML))
 			if err != nil {
 				return err
@@ -66,6 +88,13 @@ func newCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&filename, "filename", "f", "", "name of the file to add")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "prompt for the fields of the entry that are not set by flags")
+	cmd.Flags().StringSliceVar(&newEntry.ChangeLogs, "change-logs", nil, "change logs in which the entry is included, defaults to the default change logs")
+	cmd.Flags().StringVar(&newEntry.ChangeType, "change-type", "", "type of the change, one of the change types of the config file")
+	cmd.Flags().StringVar(&newEntry.Component, "component", "", "name of the component, or a single word describing the area of concern")
+	cmd.Flags().StringVar(&newEntry.Note, "note", "", "brief description of the change")
+	cmd.Flags().StringSliceVar(&newIssues, "issues", nil, "tracking issues related to the change, as numbers, owner/repo#number or URLs")
+	cmd.Flags().StringVar(&newEntry.SubText, "subtext", "", "additional information to render under the note")
 	if err := cmd.MarkFlagRequired("filename"); err != nil {
 		cmd.PrintErrf("could not mark filename flag as required: %v", err)
 		os.Exit(1)
@@ -73,6 +102,177 @@ func newCmd() *cobra.Command {
 	return cmd
 }
 
+// writeEntry validates the entry set by flags, after prompting for its missing fields in interactive mode,
+// and writes it to path.
+func writeEntry(cmd *cobra.Command, path string) error {
+	entry := newEntry
+	for _, issue := range newIssues {
+		entry.Issues = append(entry.Issues, chlog.Issue(issue))
+	}
+	if interactive {
+		if err := promptEntry(cmd, &entry); err != nil {
+			return err
+		}
+	}
+	if err := validateEntry(&entry); err != nil {
+		return err
+	}
+	entryBytes, err := yaml.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Clean(path), entryBytes, os.FileMode(0644)); err != nil {
+		return err
+	}
+	cmd.Printf("Changelog entry written to: %s\n", path)
+	return nil
+}
+
+// validateEntry validates an entry against the change logs and components of the configuration.
+func validateEntry(entry *chlog.Entry) error {
+	changelogRequired := len(globalCfg.DefaultChangeLogs) == 0
+	validChangeLogs := []string{}
+	for changeLogKey := range globalCfg.ChangeLogs {
+		validChangeLogs = append(validChangeLogs, changeLogKey)
+	}
+	return entry.Validate(changelogRequired, globalCfg.ChangeTypeIDs(), globalCfg.Components, validChangeLogs...)
+}
+
+// promptEntry prompts for the fields of the entry that are not set by flags, reading answers from the input of cmd.
+// Change types, and components if the configuration lists them, are chosen by number or by name.
+func promptEntry(cmd *cobra.Command, entry *chlog.Entry) error {
+	p := prompter{cmd: cmd, in: bufio.NewReader(cmd.InOrStdin())}
+	var err error
+	if !cmd.Flags().Changed("change-logs") && len(globalCfg.ChangeLogs) > 1 {
+		keys := make([]string, 0, len(globalCfg.ChangeLogs))
+		for key := range globalCfg.ChangeLogs {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		if entry.ChangeLogs, err = p.list(fmt.Sprintf("Change logs, comma separated, among %v (empty for %v)", keys, globalCfg.DefaultChangeLogs), len(globalCfg.DefaultChangeLogs) == 0); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("change-type") {
+		if entry.ChangeType, err = p.choice("Change type", globalCfg.ChangeTypeIDs()); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("component") {
+		if len(globalCfg.Components) > 0 {
+			entry.Component, err = p.choice("Component", globalCfg.Components)
+		} else {
+			entry.Component, err = p.text("Component", true)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("note") {
+		if entry.Note, err = p.text("Note", true); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("issues") {
+		if entry.Issues, err = p.issues("Issues, comma separated"); err != nil {
+			return err
+		}
+	}
+	if !cmd.Flags().Changed("subtext") {
+		if entry.SubText, err = p.text("Subtext (optional)", false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prompter asks questions on the output of a command and reads the answers, one per line, from its input.
+// Invalid answers are asked again until the input ends.
+type prompter struct {
+	cmd *cobra.Command
+	in  *bufio.Reader
+}
+
+func (p prompter) ask(question string) (string, error) {
+	p.cmd.Printf("%s: ", question)
+	line, err := p.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+	if errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("no answer for %q: %w", question, err)
+	}
+	return strings.TrimSpace(line), err
+}
+
+func (p prompter) text(question string, required bool) (string, error) {
+	for {
+		answer, err := p.ask(question)
+		if !required && errors.Is(err, io.EOF) {
+			// optional questions may be left unanswered at the end of the input.
+			return "", nil
+		}
+		if err != nil || answer != "" || !required {
+			return answer, err
+		}
+		p.cmd.Println("A value is required.")
+	}
+}
+
+func (p prompter) choice(question string, choices []string) (string, error) {
+	for i, c := range choices {
+		p.cmd.Printf("  %d) %s\n", i+1, c)
+	}
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		if slices.Contains(choices, answer) {
+			return answer, nil
+		}
+		p.cmd.Printf("%q is not a valid choice, enter a number between 1 and %d or one of the names.\n", answer, len(choices))
+	}
+}
+
+func (p prompter) list(question string, required bool) ([]string, error) {
+	answer, err := p.text(question, required)
+	if err != nil || answer == "" {
+		return nil, err
+	}
+	var values []string
+	for v := range strings.SplitSeq(answer, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func (p prompter) issues(question string) ([]chlog.Issue, error) {
+	for {
+		values, err := p.list(question, true)
+		if err != nil {
+			return nil, err
+		}
+		issues := make([]chlog.Issue, 0, len(values))
+		for _, v := range values {
+			issue := chlog.Issue(strings.TrimPrefix(v, "#"))
+			if issue.Validate() != nil {
+				break
+			}
+			issues = append(issues, issue)
+		}
+		if len(issues) == len(values) {
+			return issues, nil
+		}
+		p.cmd.Println("Issues must be numbers, owner/repo#number or URLs.")
+	}
+}
+
 func cleanFileName(filename string) string {
 	replace := strings.NewReplacer("/", "_", "\\", "_")
 	return replace.Replace(filename)
@@ -17,10 +17,14 @@ package cmd
 import (
 	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
 	"go.opentelemetry.io/build-tools/chloggen/internal/config"
@@ -30,8 +34,15 @@ const newUsage = `Usage:
   chloggen new [flags]
 
 Flags:
-  -f, --filename string   name of the file to add
-  -h, --help              help for new
+      --change-logs strings   change logs in which the entry is included, defaults to the default change logs
+      --change-type string    type of the change, one of the change types of the config file
+      --component string      name of the component, or a single word describing the area of concern
+  -f, --filename string       name of the file to add
+  -h, --help                  help for new
+  -i, --interactive           prompt for the fields of the entry that are not set by flags
+      --issues strings        tracking issues related to the change, as numbers, owner/repo#number or URLs
+      --note string           brief description of the change
+      --subtext string        additional information to render under the note
 
 Global Flags:
       --config string   (optional) chloggen config file`
@@ -85,6 +96,89 @@ func TestNew(t *testing.T) {
 	assert.Empty(t, err)
 }
 
+func TestNewWithFlags(t *testing.T) {
+	globalCfg = config.New(t.TempDir())
+	globalCfg.Components = []string{"receiver/foo", "testbed"}
+	setupTestDir(t, []*chlog.Entry{})
+
+	out, err := runCobra(t, "new", "--filename", "my-change", "--change-type", "bug_fix", "--component", "testbed",
+		"--note", "Fix blah", "--issues", "12346,open-telemetry/opentelemetry-collector#12347", "--subtext", "More details")
+	require.NoError(t, err)
+	path := filepath.Join(globalCfg.EntriesDir, "my-change.yaml")
+	assert.Contains(t, out, fmt.Sprintf("Changelog entry written to: %s", path))
+	assert.Equal(t, &chlog.Entry{
+		ChangeLogs: []string{},
+		ChangeType: chlog.BugFix,
+		Component:  "testbed",
+		Note:       "Fix blah",
+		Issues:     []chlog.Issue{"12346", "open-telemetry/opentelemetry-collector#12347"},
+		SubText:    "More details",
+	}, readEntry(t, path))
+	entryBytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(entryBytes), "issues:\n    - 12346\n    - open-telemetry/opentelemetry-collector#12347\n")
+
+	_, err = runCobra(t, "new", "--filename", "invalid-change", "--change-type", "fix", "--component", "other")
+	assert.ErrorContains(t, err, "'fix' is not a valid 'change_type'")
+	assert.ErrorContains(t, err, "other is not a valid 'component'")
+	assert.ErrorContains(t, err, "specify a 'note'")
+	assert.NoFileExists(t, filepath.Join(globalCfg.EntriesDir, "invalid-change.yaml"))
+
+	_, err = runCobra(t, "new", "--filename", "invalid-issue", "--change-type", "bug_fix", "--component", "testbed",
+		"--note", "Fix blah", "--issues", "open-telemetry#12346")
+	assert.ErrorContains(t, err, "'open-telemetry#12346' is not a valid issue")
+	assert.NoFileExists(t, filepath.Join(globalCfg.EntriesDir, "invalid-issue.yaml"))
+}
+
+func TestNewInteractive(t *testing.T) {
+	globalCfg = config.New(t.TempDir())
+	globalCfg.Components = []string{"receiver/foo", "testbed"}
+	setupTestDir(t, []*chlog.Entry{})
+
+	input := strings.Join([]string{
+		"fix",          // not a valid change type
+		"4",            // enhancement
+		"receiver/foo", // component by name
+		"",             // a note is required
+		"Add some bar",
+		"abc",                            // issues must be numbers, owner/repo#number or URLs
+		"#12345, open-telemetry/foo#123", // issues
+		"",                               // no subtext
+	}, "\n")
+	out, err := runCobraWithInput(t, input, "new", "--filename", "my-change", "--interactive")
+	require.NoError(t, err)
+	assert.Contains(t, out, "  4) enhancement\n")
+	assert.Contains(t, out, `"fix" is not a valid choice`)
+	assert.Contains(t, out, "A value is required.")
+	assert.Contains(t, out, "Issues must be numbers, owner/repo#number or URLs.")
+	path := filepath.Join(globalCfg.EntriesDir, "my-change.yaml")
+	assert.Equal(t, &chlog.Entry{
+		ChangeLogs: []string{},
+		ChangeType: chlog.Enhancement,
+		Component:  "receiver/foo",
+		Note:       "Add some bar",
+		Issues:     []chlog.Issue{"12345", "open-telemetry/foo#123"},
+	}, readEntry(t, path))
+
+	// fields set by flags are not prompted for.
+	out, err = runCobraWithInput(t, "Add some bar\n", "new", "--filename", "other-change", "--interactive",
+		"--change-type", "enhancement", "--component", "testbed", "--issues", "1", "--subtext", "details")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "Change type")
+	assert.Equal(t, "Add some bar", readEntry(t, filepath.Join(globalCfg.EntriesDir, "other-change.yaml")).Note)
+
+	_, err = runCobraWithInput(t, "", "new", "--filename", "no-answer", "--interactive")
+	assert.EqualError(t, err, `no answer for "Change type": EOF`)
+}
+
+func readEntry(t *testing.T, path string) *chlog.Entry {
+	entryBytes, err := os.ReadFile(filepath.Clean(path))
+	require.NoError(t, err)
+	entry := &chlog.Entry{}
+	require.NoError(t, yaml.Unmarshal(entryBytes, entry))
+	return entry
+}
+
 func TestCleanFilename(t *testing.T) {
 	assert.Equal(t, "fix_some_bug", cleanFileName("fix/some_bug"))
 	assert.Equal(t, "fix_some_bug", cleanFileName("fix\\some_bug"))
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+var (
+	format    string
+	changeLog string
+	output    string
+	release   chlog.Release
+)
+
+func renderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Renders the new changes without updating CHANGELOG.MD",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if release.Repository != "" && !config.ValidRepository(release.Repository) {
+				return fmt.Errorf("--repo must be a GitHub repository as owner/repo, got %q", release.Repository)
+			}
+			entriesByChangelog, err := chlog.ReadEntries(globalCfg)
+			if err != nil {
+				return err
+			}
+			key, err := renderedChangeLog()
+			if err != nil {
+				return err
+			}
+			entries := entriesByChangelog[key]
+			slices.SortFunc(entries, func(a, b *chlog.Entry) int {
+				return strings.Compare(a.Component, b.Component)
+			})
+			if componentFilter != "" {
+				entries = slices.DeleteFunc(entries, func(e *chlog.Entry) bool {
+					return e.Component != componentFilter
+				})
+			}
+
+			r := release
+			if r.Date == "" && format == chlog.FormatKeepAChangelog {
+				r.Date = time.Now().Format(time.DateOnly)
+			}
+			rendered, err := chlog.Render(format, r, entries, globalCfg)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				cmd.Print(rendered)
+				return nil
+			}
+			if err = os.WriteFile(filepath.Clean(output), []byte(rendered), 0600); err != nil {
+				return err
+			}
+			cmd.Printf("Rendered %s to %s\n", key, output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", chlog.FormatMarkdown, fmt.Sprintf("format of the entries, one of %v", chlog.Formats))
+	cmd.Flags().StringVarP(&release.Version, "version", "v", "vTODO", "will be rendered directly into the text")
+	cmd.Flags().StringVar(&release.Date, "date", "", "date of the release rendered by the json and keepachangelog formats, defaults to today for keepachangelog")
+	cmd.Flags().StringVar(&release.Repository, "repo", "", "GitHub repository, as owner/name, issue numbers link to, defaults to the repository of the config file")
+	cmd.Flags().StringVar(&changeLog, "change-log", "", "change log whose entries are rendered, required if the config file has more than one")
+	cmd.Flags().StringVarP(&componentFilter, "component", "c", "", "only select entries with this exact component")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write to, instead of stdout")
+	return cmd
+}
+
+// renderedChangeLog returns the key of the change log set by the change-log flag, or of the only change log of the
+// configuration.
+func renderedChangeLog() (string, error) {
+	keys := make([]string, 0, len(globalCfg.ChangeLogs))
+	for key := range globalCfg.ChangeLogs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	switch {
+	case changeLog != "" && !slices.Contains(keys, changeLog):
+		return "", fmt.Errorf("'%s' is not a valid change log. Specify one of %v", changeLog, keys)
+	case changeLog != "":
+		return changeLog, nil
+	case len(keys) == 1:
+		return keys[0], nil
+	default:
+		return "", fmt.Errorf("specify a change log to render with --change-log, one of %v", keys)
+	}
+}
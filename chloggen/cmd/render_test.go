@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+const renderUsage = `Usage:
+  chloggen render [flags]
+
+Flags:
+      --change-log string   change log whose entries are rendered, required if the config file has more than one
+  -c, --component string    only select entries with this exact component
+      --date string         date of the release rendered by the json and keepachangelog formats, defaults to today for keepachangelog
+      --format string       format of the entries, one of [markdown json github keepachangelog] (default "markdown")
+  -h, --help                help for render
+  -o, --output string       file to write to, instead of stdout
+      --repo string         GitHub repository, as owner/name, issue numbers link to, defaults to the repository of the config file
+  -v, --version string      will be rendered directly into the text (default "vTODO")
+
+Global Flags:
+      --config string   (optional) chloggen config file`
+
+func TestRenderErr(t *testing.T) {
+	globalCfg = config.New(t.TempDir())
+	setupTestDir(t, getSampleEntries())
+
+	out, err := runCobra(t, "render", "--help")
+	assert.Contains(t, out, renderUsage)
+	assert.NoError(t, err)
+
+	_, err = runCobra(t, "render", "--format", "html")
+	assert.ErrorContains(t, err, `unknown format "html"`)
+
+	_, err = runCobra(t, "render", "--repo", "https://github.com/open-telemetry/foo")
+	assert.ErrorContains(t, err, `--repo must be a GitHub repository as owner/repo, got "https://github.com/open-telemetry/foo"`)
+
+	_, err = runCobra(t, "render", "--change-log", "api")
+	assert.ErrorContains(t, err, "'api' is not a valid change log. Specify one of [default]")
+
+	globalCfg.ChangeLogs["api"] = filepath.Join(filepath.Dir(globalCfg.EntriesDir), "CHANGELOG-API.md")
+	_, err = runCobra(t, "render")
+	assert.ErrorContains(t, err, "specify a change log to render with --change-log, one of [api default]")
+}
+
+func TestRender(t *testing.T) {
+	globalCfg = config.New(t.TempDir())
+	setupTestDir(t, []*chlog.Entry{enhancementEntry(), bugFixEntry(), deprecationEntry()})
+	changelog, err := os.ReadFile(globalCfg.ChangeLogs[config.DefaultChangeLogKey])
+	require.NoError(t, err)
+
+	out, err := runCobra(t, "render", "--format", "github", "--repo", "open-telemetry/opentelemetry-go-build-tools", "--component", "testbed")
+	require.NoError(t, err)
+	assert.Equal(t, "### 🧰 Bug fixes 🧰\n\n"+
+		"- `testbed`: Fix blah ([#12346](https://github.com/open-telemetry/opentelemetry-go-build-tools/issues/12346), "+
+		"[#12347](https://github.com/open-telemetry/opentelemetry-go-build-tools/issues/12347))\n", out)
+
+	out, err = runCobra(t, "render", "--format", "keepachangelog", "--version", "v0.45.0")
+	require.NoError(t, err)
+	assert.Contains(t, out, "## [v0.45.0] - "+time.Now().Format(time.DateOnly)+"\n")
+
+	output := filepath.Join(t.TempDir(), "release.json")
+	out, err = runCobra(t, "render", "--format", "json", "--version", "v0.45.0", "--date", "2024-01-02", "--output", output)
+	require.NoError(t, err)
+	assert.Equal(t, "Rendered default to "+output+"\n", out)
+	b, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var rendered struct {
+		Version  string `json:"version"`
+		Date     string `json:"date"`
+		Sections []struct {
+			ChangeType string         `json:"change_type"`
+			Entries    []*chlog.Entry `json:"entries"`
+		} `json:"sections"`
+	}
+	require.NoError(t, json.Unmarshal(b, &rendered))
+	assert.Equal(t, "v0.45.0", rendered.Version)
+	assert.Equal(t, "2024-01-02", rendered.Date)
+	require.Len(t, rendered.Sections, 3)
+	assert.Equal(t, chlog.Deprecation, rendered.Sections[0].ChangeType)
+	assert.Equal(t, []*chlog.Entry{deprecationEntry()}, rendered.Sections[0].Entries)
+	assert.Equal(t, chlog.Enhancement, rendered.Sections[1].ChangeType)
+	assert.Equal(t, chlog.BugFix, rendered.Sections[2].ChangeType)
+
+	out, err = runCobra(t, "render", "--format", "json", "--version", "v0.45.0")
+	require.NoError(t, err)
+	assert.NotContains(t, out, `"date"`, "json is only dated with --date")
+
+	// The change log is not updated and the entries are not deleted.
+	actualChangelog, err := os.ReadFile(globalCfg.ChangeLogs[config.DefaultChangeLogKey])
+	require.NoError(t, err)
+	assert.Equal(t, string(changelog), string(actualChangelog))
+	remaining, err := filepath.Glob(filepath.Join(globalCfg.EntriesDir, "*.yaml"))
+	require.NoError(t, err)
+	assert.Len(t, remaining, 4)
+}
@@ -38,6 +38,7 @@ func rootCmd() *cobra.Command {
 	cmd.SetOut(os.Stdout)
 	cmd.PersistentFlags().StringVar(&configFile, "config", "", "(optional) chloggen config file")
 	cmd.AddCommand(newCmd())
+	cmd.AddCommand(renderCmd())
 	cmd.AddCommand(updateCmd())
 	cmd.AddCommand(validateCmd())
 	return cmd
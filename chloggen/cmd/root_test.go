@@ -29,6 +29,7 @@ Available Commands:
   completion  Generate the autocompletion script for the specified shell
   help        Help about any command
   new         Creates new change file
+  render      Renders the new changes without updating CHANGELOG.MD
   update      Updates CHANGELOG.MD to include all new changes
   validate    Validates the files in the changelog directory
 
@@ -198,25 +198,25 @@ func TestUpdate(t *testing.T) {
 					ChangeType: "enhancement",
 					Component:  "receiver/foo",
 					Note:       "Some change",
-					Issues:     []int{1},
+					Issues:     []chlog.Issue{"1"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/bar",
 					Note:       "Some other change",
-					Issues:     []int{2},
+					Issues:     []chlog.Issue{"2"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/foobar",
 					Note:       "Some other change for foobar",
-					Issues:     []int{3},
+					Issues:     []chlog.Issue{"3"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/foo",
 					Note:       "One more foo change",
-					Issues:     []int{4},
+					Issues:     []chlog.Issue{"4"},
 				},
 			},
 			componentFilter: "receiver/foo",
@@ -229,25 +229,25 @@ func TestUpdate(t *testing.T) {
 					ChangeType: "enhancement",
 					Component:  "receiver/foo",
 					Note:       "Some change",
-					Issues:     []int{1},
+					Issues:     []chlog.Issue{"1"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/bar",
 					Note:       "Some other change",
-					Issues:     []int{2},
+					Issues:     []chlog.Issue{"2"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/foobar",
 					Note:       "Some other change for foobar",
-					Issues:     []int{3},
+					Issues:     []chlog.Issue{"3"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/foo",
 					Note:       "One more foo change",
-					Issues:     []int{4},
+					Issues:     []chlog.Issue{"4"},
 				},
 			},
 			componentFilter: "receiver/foob",
@@ -259,25 +259,25 @@ func TestUpdate(t *testing.T) {
 					ChangeType: "enhancement",
 					Component:  "receiver/a",
 					Note:       "Some change",
-					Issues:     []int{1},
+					Issues:     []chlog.Issue{"1"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/bb",
 					Note:       "One more bb change",
-					Issues:     []int{4},
+					Issues:     []chlog.Issue{"4"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/b",
 					Note:       "Some other change",
-					Issues:     []int{3},
+					Issues:     []chlog.Issue{"3"},
 				},
 				{
 					ChangeType: "enhancement",
 					Component:  "receiver/aa",
 					Note:       "Some other change for aa",
-					Issues:     []int{2},
+					Issues:     []chlog.Issue{"2"},
 				},
 			},
 			version: "v0.45.0",
@@ -16,13 +16,22 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"go.opentelemetry.io/build-tools/chloggen/internal/changes"
 	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
 )
 
+var (
+	baseRef string
+	labels  []string
+)
+
 func validateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "validate",
@@ -39,12 +48,7 @@ func validateCmd() *cobra.Command {
 			var errs error
 			for _, entries := range entriesByChangelog {
 				for _, entry := range entries {
-					changelogRequired := len(globalCfg.DefaultChangeLogs) == 0
-					validChangeLogs := []string{}
-					for changeLogKey := range globalCfg.ChangeLogs {
-						validChangeLogs = append(validChangeLogs, changeLogKey)
-					}
-					if err = entry.Validate(changelogRequired, globalCfg.Components, validChangeLogs...); err != nil {
+					if err = validateEntry(entry); err != nil {
 						errs = errors.Join(errs, err)
 					}
 				}
@@ -53,8 +57,37 @@ func validateCmd() *cobra.Command {
 				return errs
 			}
 			cmd.Printf("PASS: all files in %s/ are valid\n", globalCfg.EntriesDir)
-			return nil
+			if baseRef == "" {
+				return nil
+			}
+			return validateChanges(cmd)
 		},
 	}
+	cmd.Flags().StringVar(&baseRef, "base-ref", "", "git ref the changes are compared to, to require a new changelog entry for changes to files that are not exempt")
+	cmd.Flags().StringSliceVar(&labels, "labels", nil, "labels of the pull request, the skip label of the config file turns off the check of --base-ref")
 	return cmd
 }
+
+// validateChanges checks that a changelog entry was added since baseRef, if a changed file requires one.
+func validateChanges(cmd *cobra.Command) error {
+	if slices.Contains(labels, globalCfg.SkipLabel) {
+		cmd.Printf("SKIP: the %q label is set, no changelog entry is required\n", globalCfg.SkipLabel)
+		return nil
+	}
+	rootDir := repoRoot()
+	files, err := changes.ChangedFiles(rootDir, baseRef)
+	if err != nil {
+		return err
+	}
+	requiring, err := changes.RequiringEntry(globalCfg, rootDir, files)
+	if err != nil {
+		return err
+	}
+	if len(requiring) > 0 {
+		return fmt.Errorf("no changelog entry was added to %s/ since %s, but these files changed:\n  %s\n"+
+			"add an entry with 'chloggen new', or set the %q label if the changes do not require one",
+			globalCfg.EntriesDir, baseRef, strings.Join(requiring, "\n  "), globalCfg.SkipLabel)
+	}
+	cmd.Printf("PASS: the changes since %s have a changelog entry or do not require one\n", baseRef)
+	return nil
+}
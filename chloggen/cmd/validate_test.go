@@ -17,9 +17,14 @@ package cmd
 import (
 	"fmt"
 	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/build-tools/chloggen/internal/chlog"
 	"go.opentelemetry.io/build-tools/chloggen/internal/config"
@@ -29,7 +34,9 @@ const validateUsage = `Usage:
   chloggen validate [flags]
 
 Flags:
-  -h, --help   help for validate
+      --base-ref string   git ref the changes are compared to, to require a new changelog entry for changes to files that are not exempt
+  -h, --help              help for validate
+      --labels strings    labels of the pull request, the skip label of the config file turns off the check of --base-ref
 
 Global Flags:
       --config string   (optional) chloggen config file`
@@ -65,7 +72,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: "fake",
 					Component:  "receiver/foo",
 					Note:       "Add some bar",
-					Issues:     []int{12345},
+					Issues:     []chlog.Issue{"12345"},
 				})
 			}(),
 			wantErr: "'fake' is not a valid 'change_type'",
@@ -77,7 +84,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: chlog.BugFix,
 					Component:  "",
 					Note:       "Add some bar",
-					Issues:     []int{12345},
+					Issues:     []chlog.Issue{"12345"},
 				})
 			}(),
 			wantErr: "specify a 'component'",
@@ -89,7 +96,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: chlog.BugFix,
 					Component:  " ",
 					Note:       "Add some bar",
-					Issues:     []int{12345},
+					Issues:     []chlog.Issue{"12345"},
 				})
 			}(),
 			wantErr: "specify a 'component'",
@@ -101,7 +108,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: chlog.BugFix,
 					Component:  "receiver/foo",
 					Note:       "",
-					Issues:     []int{12345},
+					Issues:     []chlog.Issue{"12345"},
 				})
 			}(),
 			wantErr: "specify a 'note'",
@@ -113,7 +120,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: chlog.BugFix,
 					Component:  "receiver/foo",
 					Note:       " ",
-					Issues:     []int{12345},
+					Issues:     []chlog.Issue{"12345"},
 				})
 			}(),
 			wantErr: "specify a 'note'",
@@ -125,7 +132,7 @@ func TestValidate(t *testing.T) {
 					ChangeType: chlog.BugFix,
 					Component:  "receiver/foo",
 					Note:       "Add some bar",
-					Issues:     []int{},
+					Issues:     []chlog.Issue{},
 				})
 			}(),
 			wantErr: "specify one or more issues #'s",
@@ -155,6 +162,14 @@ func TestValidate(t *testing.T) {
 			}(),
 			wantErr: "foo is not a valid 'component'. It must be one of [github.com/foo/bar/receiver github.com/foo/bar/exporter]",
 		},
+		{
+			name: "custom_change_types",
+			cfgFn: func(cfg *config.Config) {
+				cfg.ChangeTypes = []config.ChangeType{{ID: "added", Heading: "Added"}, {ID: chlog.BugFix, Heading: "Fixed"}}
+			},
+			entries: []*chlog.Entry{bugFixEntry(), enhancementEntry()},
+			wantErr: "'enhancement' is not a valid 'change_type'. Specify one of [added bug_fix]",
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -183,13 +198,13 @@ func TestValidateAccumulatesErrors(t *testing.T) {
 			ChangeType: "fake_type",
 			Component:  "component",
 			Note:       "note",
-			Issues:     []int{123},
+			Issues:     []chlog.Issue{"123"},
 		},
 		{
 			ChangeType: "bug_fix",
 			Component:  "",
 			Note:       "note",
-			Issues:     []int{456},
+			Issues:     []chlog.Issue{"456"},
 		},
 	}
 
@@ -203,3 +218,82 @@ func TestValidateAccumulatesErrors(t *testing.T) {
 	assert.ErrorContains(t, err, "'fake_type' is not a valid 'change_type'")
 	assert.ErrorContains(t, err, "specify a 'component'")
 }
+
+func TestValidateChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		entry   bool
+		labels  string
+		wantOut string
+		wantErr string
+	}{
+		{
+			name:    "no_changes",
+			wantOut: "PASS: the changes since main have a changelog entry or do not require one",
+		},
+		{
+			name:    "exempt_changes",
+			files:   []string{"foo/foo_test.go", "docs/foo.md"},
+			wantOut: "PASS: the changes since main have a changelog entry or do not require one",
+		},
+		{
+			name:    "changes_with_entry",
+			files:   []string{"foo/foo.go"},
+			entry:   true,
+			wantOut: "PASS: the changes since main have a changelog entry or do not require one",
+		},
+		{
+			name:    "changes_without_entry",
+			files:   []string{"foo/foo.go", "foo/foo_test.go"},
+			wantErr: "but these files changed:\n  foo/foo.go\nadd an entry with 'chloggen new', or set the \"Skip Changelog\" label",
+		},
+		{
+			name:    "changes_with_skip_label",
+			files:   []string{"foo/foo.go"},
+			labels:  "dependencies,Skip Changelog",
+			wantOut: `SKIP: the "Skip Changelog" label is set, no changelog entry is required`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			globalCfg = config.New(rootDir)
+			setupTestDir(t, getSampleEntries())
+			git := func(args ...string) {
+				cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+				cmd.Dir = rootDir
+				out, err := cmd.CombinedOutput()
+				require.NoError(t, err, string(out))
+			}
+			git("init", "-b", "main")
+			git("add", "-A")
+			git("commit", "-m", "initial")
+			git("checkout", "-b", "feature")
+			for _, file := range tc.files {
+				path := filepath.Join(rootDir, file)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), os.FileMode(0o755)))
+				require.NoError(t, os.WriteFile(path, []byte(file), os.FileMode(0o600)))
+			}
+			if tc.entry {
+				entryBytes, err := yaml.Marshal(enhancementEntry())
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(globalCfg.EntriesDir, "foo.yaml"), entryBytes, os.FileMode(0o600)))
+			}
+			t.Chdir(rootDir)
+
+			args := []string{"validate", "--base-ref", "main"}
+			if tc.labels != "" {
+				args = append(args, "--labels", tc.labels)
+			}
+			out, err := runCobra(t, args...)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Contains(t, out, tc.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package changes finds the files changed in a git repository, to require a changelog entry
+// for the changes that need one.
+package changes
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+// File is a file changed since the merge base of a git ref and HEAD.
+type File struct {
+	// Path is the path of the file, relative to the root directory, with forward slashes.
+	Path string
+	// Added reports whether the file was added, including untracked files.
+	Added bool
+}
+
+// ChangedFiles returns the files changed since the merge base of baseRef and HEAD in the git repository
+// holding rootDir, including uncommitted and untracked changes, relative to rootDir.
+// Renamed files are reported as added under their new path.
+func ChangedFiles(rootDir string, baseRef string) ([]File, error) {
+	mergeBase, err := git(rootDir, "merge-base", baseRef, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git(rootDir, "diff", "--name-status", "--relative", "-M", "-z", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	// with -z, each record is a status followed by a path, or by the old and new paths of renamed and copied files,
+	// all NUL-terminated and neither quoted nor escaped.
+	var files []File
+	fields := strings.Split(strings.TrimSuffix(diff, "\x00"), "\x00")
+	for i := 0; i < len(fields) && fields[i] != ""; i++ {
+		status := fields[i][0]
+		if status == 'R' || status == 'C' {
+			// the old path is followed by the new path.
+			i++
+		}
+		if i+1 >= len(fields) {
+			break
+		}
+		i++
+		files = append(files, File{Path: fields[i], Added: status == 'A' || status == 'R' || status == 'C'})
+	}
+	untracked, err := git(rootDir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	for p := range strings.SplitSeq(untracked, "\x00") {
+		if p != "" {
+			files = append(files, File{Path: p, Added: true})
+		}
+	}
+	return files, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// RequiringEntry returns the paths of the changed files that require a changelog entry, if no entry was added.
+// Files under the entries directory and files matching the exempt globs of the configuration do not require one.
+// rootDir is the directory the paths of the files are relative to.
+func RequiringEntry(cfg *config.Config, rootDir string, files []File) ([]string, error) {
+	entriesDir, err := filepath.Rel(rootDir, cfg.EntriesDir)
+	if err != nil {
+		return nil, err
+	}
+	entriesDir = filepath.ToSlash(entriesDir)
+	var requiring []string
+	for _, f := range files {
+		if path.Dir(f.Path) == entriesDir {
+			if f.Added && isEntry(cfg, rootDir, f.Path) {
+				return nil, nil
+			}
+			continue
+		}
+		exempt := false
+		for _, glob := range cfg.ExemptGlobs {
+			if exempt, err = MatchGlob(glob, f.Path); err != nil {
+				return nil, err
+			} else if exempt {
+				break
+			}
+		}
+		if !exempt {
+			requiring = append(requiring, f.Path)
+		}
+	}
+	return requiring, nil
+}
+
+// isEntry reports whether a file of the entries directory is a changelog entry.
+func isEntry(cfg *config.Config, rootDir string, p string) bool {
+	abs := filepath.Join(rootDir, filepath.FromSlash(p))
+	ext := path.Ext(p)
+	return (ext == ".yaml" || ext == ".yml") && abs != cfg.TemplateYAML && abs != cfg.ConfigYAML
+}
+
+// MatchGlob reports whether a slash-separated path matches a glob.
+// A "**" element matches any number of directories, and a glob without a slash matches the base name of the path,
+// as in "*_test.go". Other elements match as with path.Match.
+func MatchGlob(glob string, p string) (bool, error) {
+	if !strings.Contains(glob, "/") {
+		return path.Match(glob, path.Base(p))
+	}
+	return matchElements(strings.Split(glob, "/"), strings.Split(p, "/"))
+}
+
+func matchElements(glob []string, p []string) (bool, error) {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(p); i++ {
+				if ok, err := matchElements(glob[1:], p[i:]); ok || err != nil {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(p) == 0 {
+			return false, nil
+		}
+		if ok, err := path.Match(glob[0], p[0]); !ok || err != nil {
+			return false, err
+		}
+		glob, p = glob[1:], p[1:]
+	}
+	return len(p) == 0, nil
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{glob: "*_test.go", path: "foo_test.go", want: true},
+		{glob: "*_test.go", path: "receiver/foo/foo_test.go", want: true},
+		{glob: "*_test.go", path: "receiver/foo/foo.go", want: false},
+		{glob: "docs/**", path: "docs/index.md", want: true},
+		{glob: "docs/**", path: "docs/design/index.md", want: true},
+		{glob: "docs/**", path: "receiver/docs/index.md", want: false},
+		{glob: "**/testdata/**", path: "testdata/config.yaml", want: true},
+		{glob: "**/testdata/**", path: "receiver/foo/testdata/config.yaml", want: true},
+		{glob: "**/testdata/**", path: "receiver/foo/config.yaml", want: false},
+		{glob: "receiver/*/metadata.yaml", path: "receiver/foo/metadata.yaml", want: true},
+		{glob: "receiver/*/metadata.yaml", path: "receiver/foo/bar/metadata.yaml", want: false},
+		{glob: "receiver/**/*.yaml", path: "receiver/foo/bar/metadata.yaml", want: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.glob+" "+tc.path, func(t *testing.T) {
+			got, err := MatchGlob(tc.glob, tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := MatchGlob("receiver/[/foo", "receiver/foo")
+	assert.Error(t, err)
+}
+
+func TestRequiringEntry(t *testing.T) {
+	rootDir := t.TempDir()
+	cfg := config.New(rootDir)
+	tests := []struct {
+		name  string
+		files []File
+		want  []string
+	}{
+		{
+			name: "no_changes",
+		},
+		{
+			name:  "exempt_changes",
+			files: []File{{Path: "foo/foo_test.go"}, {Path: "README.md"}, {Path: ".github/workflows/ci.yaml", Added: true}},
+		},
+		{
+			name:  "changes_without_entry",
+			files: []File{{Path: "foo/foo.go"}, {Path: "foo/foo_test.go"}, {Path: "bar/bar.go", Added: true}},
+			want:  []string{"foo/foo.go", "bar/bar.go"},
+		},
+		{
+			name:  "changes_with_entry",
+			files: []File{{Path: "foo/foo.go"}, {Path: ".chloggen/foo.yaml", Added: true}},
+		},
+		{
+			name:  "changes_with_modified_entry",
+			files: []File{{Path: "foo/foo.go"}, {Path: ".chloggen/foo.yaml"}},
+			want:  []string{"foo/foo.go"},
+		},
+		{
+			name:  "changes_with_template",
+			files: []File{{Path: "foo/foo.go"}, {Path: ".chloggen/TEMPLATE.yaml", Added: true}},
+			want:  []string{"foo/foo.go"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RequiringEntry(cfg, rootDir, tc.files)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = rootDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(path string, content string) {
+		path = filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	git("init", "-b", "main")
+	write("foo/foo.go", "package foo")
+	write("foo/old.go", "package foo\n\nfunc Old() {}")
+	write("bar/bar.go", "package bar")
+	write("bar/my file.go", "package bar")
+	git("add", "-A")
+	git("commit", "-m", "initial")
+	git("checkout", "-b", "feature")
+	write("foo/foo.go", "package foo\n\nfunc Foo() {}")
+	git("mv", "foo/old.go", "foo/new.go")
+	git("rm", "bar/bar.go")
+	// paths with spaces or non-ASCII characters are quoted by git unless -z is set.
+	write("bar/my file.go", "package bar\n\nfunc Bar() {}")
+	git("mv", "foo/new.go", "foo/renamed file.go")
+	git("commit", "-am", "change")
+	write("foo/foo_test.go", "package foo")
+	write("foo/é.go", "package foo")
+	write(".chloggen/foo.yaml", "change_type: bug_fix")
+
+	files, err := ChangedFiles(rootDir, "main")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []File{
+		{Path: "bar/bar.go"},
+		{Path: "foo/foo.go"},
+		{Path: "bar/my file.go"},
+		{Path: "foo/renamed file.go", Added: true},
+		{Path: ".chloggen/foo.yaml", Added: true},
+		{Path: "foo/foo_test.go", Added: true},
+		{Path: "foo/é.go", Added: true},
+	}, files)
+
+	_, err = ChangedFiles(rootDir, "unknown")
+	assert.ErrorContains(t, err, "git merge-base unknown HEAD")
+}
@@ -46,25 +46,16 @@ const (
 
 // Entry represents a changelog entry.
 type Entry struct {
-	ChangeLogs []string `yaml:"change_logs"`
-	ChangeType string   `yaml:"change_type"`
-	Component  string   `yaml:"component"`
-	Note       string   `yaml:"note"`
-	Issues     []int    `yaml:"issues"`
-	SubText    string   `yaml:"subtext"`
+	ChangeLogs []string `yaml:"change_logs" json:"change_logs,omitempty"`
+	ChangeType string   `yaml:"change_type" json:"change_type"`
+	Component  string   `yaml:"component" json:"component"`
+	Note       string   `yaml:"note" json:"note"`
+	Issues     []Issue  `yaml:"issues" json:"issues"`
+	SubText    string   `yaml:"subtext" json:"subtext,omitempty"`
 }
 
-var changeTypes = []string{
-	Breaking,
-	Deprecation,
-	NewComponent,
-	Enhancement,
-	BugFix,
-	Telemetry,
-}
-
-// Validate validates the changelog entry.
-func (e Entry) Validate(requireChangelog bool, components []string, validChangeLogs ...string) error {
+// Validate validates the changelog entry against the IDs of the change types of the configuration.
+func (e Entry) Validate(requireChangelog bool, changeTypes []string, components []string, validChangeLogs ...string) error {
 	var errs error
 	if requireChangelog && len(e.ChangeLogs) == 0 {
 		errs = errors.Join(errs, fmt.Errorf("specify one or more 'change_logs'"))
@@ -102,6 +93,9 @@ func (e Entry) Validate(requireChangelog bool, components []string, validChangeL
 	if len(e.Issues) == 0 {
 		errs = errors.Join(errs, fmt.Errorf("specify one or more issues #'s"))
 	}
+	for _, issue := range e.Issues {
+		errs = errors.Join(errs, issue.Validate())
+	}
 
 	return errs
 }
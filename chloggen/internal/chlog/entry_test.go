@@ -29,12 +29,7 @@ import (
 )
 
 func TestEntry(t *testing.T) {
-	tmpl := template.Must(
-		template.
-			New("summary.tmpl").
-			Funcs(TemplateFuncMap()).
-			Option("missingkey=error").
-			Parse(string(defaultTmpl)))
+	tmpl := template.Must(newTemplate("summary.tmpl", ""))
 
 	testCases := []struct {
 		name             string
@@ -55,7 +50,7 @@ func TestEntry(t *testing.T) {
 			entry: Entry{
 				ChangeType: "enhancement",
 				Note:       "enhance!",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "",
 			},
 			expectErr: "specify a 'component'",
@@ -65,7 +60,7 @@ func TestEntry(t *testing.T) {
 			entry: Entry{
 				ChangeType: "bug_fix",
 				Component:  "bar",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "",
 			},
 			expectErr: "specify a 'note'",
@@ -80,13 +75,34 @@ func TestEntry(t *testing.T) {
 			},
 			expectErr: "specify one or more issues #'s",
 		},
+		{
+			name: "invalid_issue",
+			entry: Entry{
+				ChangeType: "bug_fix",
+				Component:  "bar",
+				Note:       "fix bar",
+				Issues:     []Issue{"123", "bar#456"},
+			},
+			expectErr: "'bar#456' is not a valid issue. Specify a number, owner/repo#number or a URL",
+		},
+		{
+			name: "cross_repository_issues",
+			entry: Entry{
+				ChangeType: "bug_fix",
+				Component:  "bar",
+				Note:       "fix bar",
+				Issues:     []Issue{"123", "open-telemetry/foo#456", "https://github.com/open-telemetry/foo/pull/789"},
+			},
+			toString: "- `bar`: fix bar (#123, [open-telemetry/foo#456](https://github.com/open-telemetry/foo/issues/456), " +
+				"[open-telemetry/foo#789](https://github.com/open-telemetry/foo/pull/789))",
+		},
 		{
 			name: "missing_required_changelog",
 			entry: Entry{
 				ChangeType: "bug_fix",
 				Component:  "bar",
 				Note:       "fix bar",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "",
 			},
 			requireChangeLog: true,
@@ -100,7 +116,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "bug_fix",
 				Component:  "bar",
 				Note:       "fix bar",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "",
 			},
 			validChangeLogs: []string{"foo"},
@@ -112,7 +128,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "",
 			},
 			toString: "- `foo`: broke foo (#123)",
@@ -123,7 +139,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123, 345},
+				Issues:     []Issue{"123", "345"},
 				SubText:    "",
 			},
 			toString: "- `foo`: broke foo (#123, #345)",
@@ -134,7 +150,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			toString: "- `foo`: broke foo (#123)\n  more details",
@@ -145,7 +161,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details\nsecond line",
 			},
 			toString: "- `foo`: broke foo (#123)\n  more details\n  second line",
@@ -157,7 +173,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			requireChangeLog: true,
@@ -171,7 +187,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			requireChangeLog: false,
@@ -185,7 +201,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			validChangeLogs: []string{"foo", "bar", "baz"},
@@ -198,7 +214,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			validChangeLogs: []string{"foo", "bar"},
@@ -211,7 +227,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "breaking",
 				Component:  "foo",
 				Note:       "broke foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			validChangeLogs: []string{"foo", "bar"},
@@ -224,7 +240,7 @@ func TestEntry(t *testing.T) {
 				ChangeType: "enhancement",
 				Component:  "foo",
 				Note:       "changed foo",
-				Issues:     []int{123},
+				Issues:     []Issue{"123"},
 				SubText:    "more details",
 			},
 			components:      []string{"bar"},
@@ -236,7 +252,7 @@ func TestEntry(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := tc.entry.Validate(tc.requireChangeLog, tc.components, tc.validChangeLogs...)
+			err := tc.entry.Validate(tc.requireChangeLog, config.New("").ChangeTypeIDs(), tc.components, tc.validChangeLogs...)
 			if tc.expectErr != "" {
 				assert.Error(t, err)
 				assert.Equal(t, tc.expectErr, err.Error())
@@ -263,7 +279,7 @@ func TestReadDeleteEntries(t *testing.T) {
 		ChangeType: "breaking",
 		Component:  "foo",
 		Note:       "broke foo",
-		Issues:     []int{123},
+		Issues:     []Issue{"123"},
 	}
 	writeEntry(t, entriesDir, &entryA, "yaml")
 
@@ -272,7 +288,7 @@ func TestReadDeleteEntries(t *testing.T) {
 		ChangeType: "bug_fix",
 		Component:  "bar",
 		Note:       "fix bar",
-		Issues:     []int{345, 678},
+		Issues:     []Issue{"345", "678"},
 		SubText:    "more details",
 	}
 	writeEntry(t, entriesDir, &entryB, "yml")
@@ -282,7 +298,7 @@ func TestReadDeleteEntries(t *testing.T) {
 		ChangeType: "enhancement",
 		Component:  "other",
 		Note:       "enhance!",
-		Issues:     []int{555},
+		Issues:     []Issue{"555"},
 	}
 	writeEntry(t, entriesDir, &entryC, "yaml")
 
@@ -291,7 +307,7 @@ func TestReadDeleteEntries(t *testing.T) {
 		ChangeType: "deprecation",
 		Component:  "foobar",
 		Note:       "deprecate something",
-		Issues:     []int{999},
+		Issues:     []Issue{"999"},
 	}
 	writeEntry(t, entriesDir, &entryD, "yml")
 
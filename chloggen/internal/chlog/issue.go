@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue references an issue or a pull request, as a number in the repository of the changelog,
+// as owner/repo#number in another GitHub repository, or as a URL.
+type Issue string
+
+var (
+	issueRefPattern    = regexp.MustCompile(`^(?:([A-Za-z0-9-]+/[A-Za-z0-9._-]+)#)?([0-9]+)$`)
+	githubIssuePattern = regexp.MustCompile(`^/([A-Za-z0-9-]+/[A-Za-z0-9._-]+)/(?:issues|pull)/([0-9]+)/?$`)
+)
+
+// issueRef is a parsed issue reference. Repository and Number are set for references to GitHub issues and pull requests.
+type issueRef struct {
+	Repository string
+	Number     string
+	URL        string
+}
+
+func (i Issue) parse() (issueRef, error) {
+	if m := issueRefPattern.FindStringSubmatch(string(i)); m != nil {
+		return issueRef{Repository: m[1], Number: m[2]}, nil
+	}
+	u, err := url.Parse(string(i))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return issueRef{}, fmt.Errorf("'%s' is not a valid issue. Specify a number, owner/repo#number or a URL", i)
+	}
+	ref := issueRef{URL: string(i)}
+	if u.Host == "github.com" {
+		if m := githubIssuePattern.FindStringSubmatch(u.Path); m != nil {
+			ref.Repository, ref.Number = m[1], m[2]
+		}
+	}
+	return ref, nil
+}
+
+// Validate checks that the issue is a number, owner/repo#number or a http or https URL.
+func (i Issue) Validate() error {
+	_, err := i.parse()
+	return err
+}
+
+// Markdown renders the issue, linked to it if it is in another repository, if it is a URL,
+// or if the repository of the changelog is set as owner/repo.
+// References to the issues of the repository of the changelog are rendered as #number, and references
+// to the issues of other GitHub repositories as owner/repo#number.
+func (i Issue) Markdown(repository string) string {
+	ref, err := i.parse()
+	if err != nil {
+		return string(i)
+	}
+	if ref.Number == "" {
+		return fmt.Sprintf("[%s](%s)", ref.URL, ref.URL)
+	}
+	text := "#" + ref.Number
+	if ref.Repository != "" && !strings.EqualFold(ref.Repository, repository) {
+		text = ref.Repository + text
+	}
+	link := ref.URL
+	switch {
+	case link != "":
+	case ref.Repository != "":
+		link = fmt.Sprintf("https://github.com/%s/issues/%s", ref.Repository, ref.Number)
+	case repository != "":
+		link = fmt.Sprintf("https://github.com/%s/issues/%s", repository, ref.Number)
+	default:
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, link)
+}
+
+// MarshalYAML writes issue numbers as YAML integers, as they were before issues could reference other repositories.
+func (i Issue) MarshalYAML() (any, error) {
+	if n, err := strconv.Atoi(string(i)); err == nil {
+		return n, nil
+	}
+	return string(i), nil
+}
+
+// UnmarshalYAML reads issues written as YAML integers or strings.
+func (i *Issue) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: an issue must be a number, owner/repo#number or a URL", value.Line)
+	}
+	*i = Issue(value.Value)
+	return nil
+}
+
+// MarshalJSON writes issue numbers as JSON numbers.
+func (i Issue) MarshalJSON() ([]byte, error) {
+	if n, err := strconv.Atoi(string(i)); err == nil {
+		return json.Marshal(n)
+	}
+	return json.Marshal(string(i))
+}
+
+// UnmarshalJSON reads issues written as JSON numbers or strings.
+func (i *Issue) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err == nil {
+		*i = Issue(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*i = Issue(s)
+	return nil
+}
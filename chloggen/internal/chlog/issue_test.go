@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chlog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestIssue(t *testing.T) {
+	tests := []struct {
+		issue       Issue
+		expectErr   bool
+		markdown    string
+		markdownFoo string
+	}{
+		{
+			issue:       "123",
+			markdown:    "#123",
+			markdownFoo: "[#123](https://github.com/open-telemetry/foo/issues/123)",
+		},
+		{
+			issue:       "open-telemetry/bar#45",
+			markdown:    "[open-telemetry/bar#45](https://github.com/open-telemetry/bar/issues/45)",
+			markdownFoo: "[open-telemetry/bar#45](https://github.com/open-telemetry/bar/issues/45)",
+		},
+		{
+			issue:       "open-telemetry/foo#45",
+			markdown:    "[open-telemetry/foo#45](https://github.com/open-telemetry/foo/issues/45)",
+			markdownFoo: "[#45](https://github.com/open-telemetry/foo/issues/45)",
+		},
+		{
+			issue:       "https://github.com/open-telemetry/bar/pull/67",
+			markdown:    "[open-telemetry/bar#67](https://github.com/open-telemetry/bar/pull/67)",
+			markdownFoo: "[open-telemetry/bar#67](https://github.com/open-telemetry/bar/pull/67)",
+		},
+		{
+			issue:       "https://github.com/open-telemetry/foo/issues/67",
+			markdown:    "[open-telemetry/foo#67](https://github.com/open-telemetry/foo/issues/67)",
+			markdownFoo: "[#67](https://github.com/open-telemetry/foo/issues/67)",
+		},
+		{
+			issue:       "https://issues.example.com/browse/FOO-1",
+			markdown:    "[https://issues.example.com/browse/FOO-1](https://issues.example.com/browse/FOO-1)",
+			markdownFoo: "[https://issues.example.com/browse/FOO-1](https://issues.example.com/browse/FOO-1)",
+		},
+		{issue: "", expectErr: true},
+		{issue: "#123", expectErr: true},
+		{issue: "abc", expectErr: true},
+		{issue: "open-telemetry#123", expectErr: true},
+		{issue: "open-telemetry/foo#", expectErr: true},
+		{issue: "open telemetry/foo#1", expectErr: true},
+		{issue: "ftp://example.com/1", expectErr: true},
+		{issue: "https:///1", expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.issue), func(t *testing.T) {
+			err := tc.issue.Validate()
+			if tc.expectErr {
+				assert.EqualError(t, err, "'"+string(tc.issue)+"' is not a valid issue. Specify a number, owner/repo#number or a URL")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.markdown, tc.issue.Markdown(""))
+			assert.Equal(t, tc.markdownFoo, tc.issue.Markdown("open-telemetry/foo"))
+		})
+	}
+}
+
+func TestIssueMarshal(t *testing.T) {
+	entry := Entry{Issues: []Issue{"123", "open-telemetry/foo#45", "https://github.com/open-telemetry/foo/pull/67"}}
+
+	b, err := yaml.Marshal(entry)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "issues:\n    - 123\n    - open-telemetry/foo#45\n    - https://github.com/open-telemetry/foo/pull/67\n")
+	var fromYAML Entry
+	require.NoError(t, yaml.Unmarshal(b, &fromYAML))
+	assert.Equal(t, entry.Issues, fromYAML.Issues)
+
+	b, err = json.Marshal(entry)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"issues":[123,"open-telemetry/foo#45","https://github.com/open-telemetry/foo/pull/67"]`)
+	var fromJSON Entry
+	require.NoError(t, json.Unmarshal(b, &fromJSON))
+	assert.Equal(t, entry.Issues, fromJSON.Issues)
+
+	assert.ErrorContains(t, yaml.Unmarshal([]byte("issues: [[123]]"), &fromYAML), "an issue must be a number, owner/repo#number or a URL")
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chlog
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+const (
+	// FormatMarkdown renders the entries with the summary template, as 'chloggen update' writes them to the changelog.
+	FormatMarkdown = "markdown"
+	// FormatJSON renders the entries as JSON, grouped by change type.
+	FormatJSON = "json"
+	// FormatGitHub renders the entries as the body of a GitHub release, linking their issues.
+	FormatGitHub = "github"
+	// FormatKeepAChangelog renders the entries as a release of a changelog following https://keepachangelog.com.
+	FormatKeepAChangelog = "keepachangelog"
+)
+
+// Formats lists the formats entries can be rendered in.
+var Formats = []string{FormatMarkdown, FormatJSON, FormatGitHub, FormatKeepAChangelog}
+
+var (
+	//go:embed templates/entry.tmpl
+	entryTmpl []byte
+	//go:embed templates/github.tmpl
+	githubTmpl []byte
+	//go:embed templates/keepachangelog.tmpl
+	keepAChangelogTmpl []byte
+)
+
+// Release describes the release the entries are rendered for.
+type Release struct {
+	// Version is the version of the release.
+	Version string
+	// Date is the date of the release, rendered by the JSON and Keep a Changelog formats.
+	Date string
+	// Repository is the GitHub repository, as owner/name, that issue numbers link to, if not the one of the configuration.
+	// Issue numbers are rendered as #123 if neither is set, which GitHub links to the issues of the repository of the release.
+	Repository string
+}
+
+// Render renders the entries in a format, grouped by the change types of the configuration.
+func Render(format string, release Release, entries []*Entry, cfg *config.Config) (string, error) {
+	s := newSummary(release.Version, entries, cfg)
+	if release.Repository != "" {
+		s.Repository = release.Repository
+	}
+	switch format {
+	case FormatMarkdown:
+		return s.String(cfg.SummaryTemplate)
+	case FormatJSON:
+		return renderJSON(release, s)
+	case FormatGitHub:
+		return renderTemplate(format, githubTmpl, release.Date, s)
+	case FormatKeepAChangelog:
+		return renderTemplate(format, keepAChangelogTmpl, release.Date, s)
+	default:
+		return "", fmt.Errorf("unknown format %q, must be one of %v", format, Formats)
+	}
+}
+
+type jsonRelease struct {
+	Version  string        `json:"version"`
+	Date     string        `json:"date,omitempty"`
+	Sections []jsonSection `json:"sections"`
+}
+
+type jsonSection struct {
+	ChangeType string   `json:"change_type"`
+	Heading    string   `json:"heading"`
+	Emoji      string   `json:"emoji,omitempty"`
+	Entries    []*Entry `json:"entries"`
+}
+
+func renderJSON(release Release, s summary) (string, error) {
+	r := jsonRelease{Version: release.Version, Date: release.Date, Sections: []jsonSection{}}
+	for _, sec := range s.Sections {
+		if len(sec.Entries) == 0 {
+			continue
+		}
+		r.Sections = append(r.Sections, jsonSection{ChangeType: sec.ID, Heading: sec.Heading, Emoji: sec.Emoji, Entries: sec.Entries})
+	}
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// newTemplate returns a template defining the "entry" template, which renders an entry as a list item.
+// The templates parsed into it may redefine it.
+func newTemplate(name string, repository string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncMap(repository)).Option("missingkey=error").Parse(string(entryTmpl))
+}
+
+func renderTemplate(name string, text []byte, date string, s summary) (string, error) {
+	tmpl, err := newTemplate(name, s.Repository)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmpl.Parse(string(text)); err != nil {
+		return "", err
+	}
+	data := struct {
+		summary
+		Date string
+	}{summary: s, Date: date}
+	buf := bytes.Buffer{}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed executing template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
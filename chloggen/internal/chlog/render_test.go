@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/build-tools/chloggen/internal/config"
+)
+
+func TestRender(t *testing.T) {
+	entries := []*Entry{
+		{ChangeType: Breaking, Component: "foo", Note: "broke foo", Issues: []Issue{"123"}},
+		{ChangeType: Breaking, Component: "bar", Note: "broke bar", Issues: []Issue{"345", "678"}, SubText: "more details"},
+		{ChangeType: BugFix, Component: "foo", Note: "bug foo", Issues: []Issue{"1"}},
+		{ChangeType: Telemetry, Component: "bar", Note: "telemetry bar", Issues: []Issue{"2"}, ChangeLogs: []string{"user"}},
+	}
+	release := Release{Version: "1.0", Date: "2024-01-02"}
+	tests := []struct {
+		format     string
+		repository string
+		golden     string
+	}{
+		{format: FormatMarkdown, golden: "markdown"},
+		{format: FormatJSON, golden: "release.json"},
+		{format: FormatGitHub, golden: "github"},
+		{format: FormatGitHub, repository: "open-telemetry/opentelemetry-go-build-tools", golden: "github_links"},
+		{format: FormatKeepAChangelog, repository: "open-telemetry/opentelemetry-go-build-tools", golden: "keepachangelog"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.golden, func(t *testing.T) {
+			r := release
+			r.Repository = tc.repository
+			actual, err := Render(tc.format, r, entries, &config.Config{})
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(filepath.Join("testdata", "render", tc.golden))
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), actual)
+		})
+	}
+
+	_, err := Render("html", release, entries, &config.Config{})
+	assert.EqualError(t, err, `unknown format "html", must be one of [markdown json github keepachangelog]`)
+}
@@ -29,7 +29,12 @@ import (
 var defaultTmpl []byte
 
 type summary struct {
-	Version         string
+	Version string
+	// Repository is the GitHub repository of the changelog, as owner/repo, that issue numbers link to.
+	Repository string
+	// Sections holds the entries of each change type of the configuration, sorted by order.
+	Sections []section
+	// The entries of the default change types are also available by name, for custom summary templates.
 	BreakingChanges []*Entry
 	Deprecations    []*Entry
 	NewComponents   []*Entry
@@ -37,13 +42,35 @@ type summary struct {
 	BugFixes        []*Entry
 }
 
+// section holds the entries of a change type.
+type section struct {
+	config.ChangeType
+	Entries []*Entry
+}
+
 // GenerateSummary generates a changelog entry summary.
 func GenerateSummary(version string, entries []*Entry, cfg *config.Config) (string, error) {
+	return newSummary(version, entries, cfg).String(cfg.SummaryTemplate)
+}
+
+// newSummary groups the entries by change type.
+func newSummary(version string, entries []*Entry, cfg *config.Config) summary {
 	s := summary{
-		Version: version,
+		Version:    version,
+		Repository: cfg.Repository,
 	}
 
+	changeTypes := cfg.OrderedChangeTypes()
+	s.Sections = make([]section, len(changeTypes))
+	for i, ct := range changeTypes {
+		s.Sections[i].ChangeType = ct
+	}
 	for _, entry := range entries {
+		for i := range s.Sections {
+			if s.Sections[i].ID == entry.ChangeType {
+				s.Sections[i].Entries = append(s.Sections[i].Entries, entry)
+			}
+		}
 		switch entry.ChangeType {
 		case Breaking:
 			s.BreakingChanges = append(s.BreakingChanges, entry)
@@ -57,37 +84,42 @@ func GenerateSummary(version string, entries []*Entry, cfg *config.Config) (stri
 			s.BugFixes = append(s.BugFixes, entry)
 		}
 	}
-
-	return s.String(cfg.SummaryTemplate)
+	return s
 }
 
 // TemplateFuncMap returns a map of functions to be used in the template.
+// The issue function renders issues without linking issue numbers to a repository.
 func TemplateFuncMap() template.FuncMap {
+	return templateFuncMap("")
+}
+
+// templateFuncMap returns the functions of the templates, rendering issues with links to the issues of repository.
+func templateFuncMap(repository string) template.FuncMap {
 	return template.FuncMap{
 		"indent": func(n int, s string) string {
 			indent := strings.Repeat(" ", n)
 			return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
 		},
+		"issue": func(issue Issue) string {
+			return issue.Markdown(repository)
+		},
 	}
 }
 
 // String renders the summary using the provided template.
 func (s summary) String(summaryTemplate string) (string, error) {
-	var tmpl *template.Template
-	var err error
-
+	name := "summary.tmpl"
+	if summaryTemplate != "" {
+		name = filepath.Base(summaryTemplate)
+	}
+	tmpl, err := newTemplate(name, s.Repository)
+	if err != nil {
+		return "", err
+	}
 	if summaryTemplate != "" {
-		tmpl, err = template.
-			New(filepath.Base(summaryTemplate)).
-			Funcs(TemplateFuncMap()).
-			Option("missingkey=error").
-			ParseFiles(summaryTemplate)
+		_, err = tmpl.ParseFiles(summaryTemplate)
 	} else {
-		tmpl, err = template.
-			New("summary.tmpl").
-			Funcs(TemplateFuncMap()).
-			Option("missingkey=error").
-			Parse(string(defaultTmpl))
+		_, err = tmpl.Parse(string(defaultTmpl))
 	}
 	if err != nil {
 		return "", err
@@ -31,65 +31,65 @@ func TestSummary(t *testing.T) {
 		ChangeType: Breaking,
 		Component:  "foo",
 		Note:       "broke foo",
-		Issues:     []int{123},
+		Issues:     []Issue{"123"},
 	}
 	brk2 := Entry{
 		ChangeType: Breaking,
 		Component:  "bar",
 		Note:       "broke bar",
-		Issues:     []int{345, 678},
+		Issues:     []Issue{"345", "678"},
 		SubText:    "more details",
 	}
 	dep1 := Entry{
 		ChangeType: Deprecation,
 		Component:  "foo",
 		Note:       "deprecate foo",
-		Issues:     []int{1234},
+		Issues:     []Issue{"1234"},
 	}
 	dep2 := Entry{
 		ChangeType: Deprecation,
 		Component:  "bar",
 		Note:       "deprecate bar",
-		Issues:     []int{3456, 6789},
+		Issues:     []Issue{"3456", "6789"},
 		SubText:    "more details",
 	}
 	enh1 := Entry{
 		ChangeType: Enhancement,
 		Component:  "foo",
 		Note:       "enhance foo",
-		Issues:     []int{12},
+		Issues:     []Issue{"12"},
 	}
 	enh2 := Entry{
 		ChangeType: Enhancement,
 		Component:  "bar",
 		Note:       "enhance bar",
-		Issues:     []int{34, 67},
+		Issues:     []Issue{"34", "67"},
 		SubText:    "more details",
 	}
 	bug1 := Entry{
 		ChangeType: BugFix,
 		Component:  "foo",
 		Note:       "bug foo",
-		Issues:     []int{1},
+		Issues:     []Issue{"1"},
 	}
 	bug2 := Entry{
 		ChangeType: BugFix,
 		Component:  "bar",
 		Note:       "bug bar",
-		Issues:     []int{3, 6},
+		Issues:     []Issue{"3", "6"},
 		SubText:    "more details",
 	}
 	new1 := Entry{
 		ChangeType: NewComponent,
 		Component:  "foo",
 		Note:       "new foo",
-		Issues:     []int{2},
+		Issues:     []Issue{"2"},
 	}
 	new2 := Entry{
 		ChangeType: NewComponent,
 		Component:  "bar",
 		Note:       "new bar",
-		Issues:     []int{4, 7},
+		Issues:     []Issue{"4", "7"},
 		SubText:    "more details",
 	}
 
@@ -108,13 +108,13 @@ func TestCustomSummary(t *testing.T) {
 		ChangeType: Breaking,
 		Component:  "foo",
 		Note:       "broke foo",
-		Issues:     []int{123},
+		Issues:     []Issue{"123"},
 	}
 	brk2 := Entry{
 		ChangeType: Breaking,
 		Component:  "bar",
 		Note:       "broke bar",
-		Issues:     []int{345, 678},
+		Issues:     []Issue{"345", "678"},
 		SubText:    "more details",
 	}
 
@@ -126,4 +126,59 @@ func TestCustomSummary(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, string(expected), actual)
+	// custom templates that do not define it render entries with the default entry template.
+	customTmpl := filepath.Join(t.TempDir(), "custom.tmpl")
+	require.NoError(t, os.WriteFile(customTmpl, []byte("## {{ .Version }}\n{{ range .BreakingChanges }}\n{{ template \"entry\" . }}{{ end }}\n"), 0o600))
+	actual, err = GenerateSummary("1.0", []*Entry{&brk1, &brk2}, &config.Config{SummaryTemplate: customTmpl})
+	require.NoError(t, err)
+	assert.Equal(t, "## 1.0\n\n- `foo`: broke foo (#123)\n- `bar`: broke bar (#345, #678)\n  more details\n", actual)
+}
+
+func TestSummaryChangeTypes(t *testing.T) {
+	cfg := &config.Config{
+		ChangeTypes: []config.ChangeType{
+			{ID: "fixed", Heading: "Fixed", Order: 2},
+			{ID: Telemetry, Heading: "Telemetry", Emoji: "📈", Order: 1},
+			{ID: "removed", Heading: "Removed", Order: 3},
+		},
+	}
+	entries := []*Entry{
+		{ChangeType: "fixed", Component: "foo", Note: "fix foo", Issues: []Issue{"1"}},
+		{ChangeType: Telemetry, Component: "bar", Note: "add bar metric", Issues: []Issue{"2"}},
+		{ChangeType: "fixed", Component: "bar", Note: "fix bar", Issues: []Issue{"3"}},
+	}
+
+	actual, err := GenerateSummary("1.0", entries, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, `
+## 1.0
+
+### 📈 Telemetry 📈
+
+- `+"`bar`"+`: add bar metric (#2)
+
+### Fixed
+
+- `+"`foo`"+`: fix foo (#1)
+- `+"`bar`"+`: fix bar (#3)
+`, actual)
+}
+
+func TestSummaryRepository(t *testing.T) {
+	entries := []*Entry{
+		{ChangeType: BugFix, Component: "foo", Note: "fix foo", Issues: []Issue{"1", "open-telemetry/bar#2", "https://github.com/open-telemetry/foo/pull/3"}},
+	}
+
+	actual, err := GenerateSummary("1.0", entries, &config.Config{Repository: "open-telemetry/foo"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `
+## 1.0
+
+### 🧰 Bug fixes 🧰
+
+- `+"`foo`"+`: fix foo ([#1](https://github.com/open-telemetry/foo/issues/1), `+
+		`[open-telemetry/bar#2](https://github.com/open-telemetry/bar/issues/2), [#3](https://github.com/open-telemetry/foo/pull/3))
+`, actual)
 }
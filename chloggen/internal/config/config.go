@@ -16,10 +16,13 @@
 package config
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
@@ -33,8 +36,43 @@ const (
 	DefaultChangeLogKey = "default"
 	// DefaultChangeLogFilename is the default filename for the changelog.
 	DefaultChangeLogFilename = "CHANGELOG.md"
+	// DefaultSkipLabel is the default label of the pull requests that do not require a changelog entry.
+	DefaultSkipLabel = "Skip Changelog"
 )
 
+// repositoryPattern matches GitHub repositories, as owner/repo.
+var repositoryPattern = regexp.MustCompile(`^[A-Za-z0-9-]+/[A-Za-z0-9._-]+$`)
+
+// ValidRepository reports whether repository is a GitHub repository, as owner/repo.
+func ValidRepository(repository string) bool {
+	return repositoryPattern.MatchString(repository)
+}
+
+// DefaultExemptGlobs are the default globs of the changed files that do not require a changelog entry.
+var DefaultExemptGlobs = []string{"*_test.go", "**/testdata/**", "*.md", "docs/**", ".github/**"}
+
+// DefaultChangeTypes are the default change types, rendered in this order.
+var DefaultChangeTypes = []ChangeType{
+	{ID: "breaking", Heading: "Breaking changes", Emoji: "🛑", Order: 1},
+	{ID: "deprecation", Heading: "Deprecations", Emoji: "🚩", Order: 2},
+	{ID: "new_component", Heading: "New components", Emoji: "🚀", Order: 3},
+	{ID: "enhancement", Heading: "Enhancements", Emoji: "💡", Order: 4},
+	{ID: "bug_fix", Heading: "Bug fixes", Emoji: "🧰", Order: 5},
+	{ID: "telemetry", Heading: "Telemetry", Emoji: "📈", Order: 6},
+}
+
+// ChangeType represents a type of change, rendered as a section of the summary.
+type ChangeType struct {
+	// ID is the value of the change_type of the entries of this type.
+	ID string `yaml:"id"`
+	// Heading is the heading of the section of the summary.
+	Heading string `yaml:"heading"`
+	// Emoji surrounds the heading of the section, if set.
+	Emoji string `yaml:"emoji"`
+	// Order sorts the sections of the summary in increasing order.
+	Order int `yaml:"order"`
+}
+
 // Config represents the configuration for changelogs.
 type Config struct {
 	ChangeLogs        map[string]string `yaml:"change_logs"`
@@ -43,6 +81,10 @@ type Config struct {
 	TemplateYAML      string            `yaml:"template_yaml"`
 	SummaryTemplate   string            `yaml:"summary_template"`
 	Components        []string          `yaml:"components"`
+	ChangeTypes       []ChangeType      `yaml:"change_types"`
+	Repository        string            `yaml:"repository"`
+	ExemptGlobs       []string          `yaml:"exempt_globs"`
+	SkipLabel         string            `yaml:"skip_label"`
 	ConfigYAML        string
 }
 
@@ -53,6 +95,8 @@ func New(rootDir string) *Config {
 		DefaultChangeLogs: []string{DefaultChangeLogKey},
 		EntriesDir:        filepath.Join(rootDir, DefaultEntriesDir),
 		TemplateYAML:      filepath.Join(rootDir, DefaultEntriesDir, DefaultTemplateYAML),
+		ExemptGlobs:       DefaultExemptGlobs,
+		SkipLabel:         DefaultSkipLabel,
 	}
 }
 
@@ -74,6 +118,19 @@ func NewFromFile(rootDir string, cfgFilename string) (*Config, error) {
 	cfg.ConfigYAML = cfgYAML
 	cfg.EntriesDir = makeAbs(rootDir, cfg.EntriesDir, DefaultEntriesDir)
 	cfg.TemplateYAML = makeAbs(rootDir, cfg.TemplateYAML, filepath.Join(DefaultEntriesDir, DefaultTemplateYAML))
+	if cfg.ExemptGlobs == nil {
+		cfg.ExemptGlobs = DefaultExemptGlobs
+	}
+	if cfg.SkipLabel == "" {
+		cfg.SkipLabel = DefaultSkipLabel
+	}
+
+	if err = validateChangeTypes(cfg.ChangeTypes); err != nil {
+		return nil, err
+	}
+	if cfg.Repository != "" && !ValidRepository(cfg.Repository) {
+		return nil, fmt.Errorf("'repository' must be a GitHub repository as owner/repo, got %q", cfg.Repository)
+	}
 
 	if len(cfg.ChangeLogs) == 0 && len(cfg.DefaultChangeLogs) > 0 {
 		return nil, errors.New("cannot specify 'default_changelogs' without 'changelogs'")
@@ -103,6 +160,46 @@ func NewFromFile(rootDir string, cfgFilename string) (*Config, error) {
 	return cfg, nil
 }
 
+// OrderedChangeTypes returns the change types of the configuration, or the default ones if it has none, sorted by order.
+// Change types of the same order keep the order of the configuration.
+func (c *Config) OrderedChangeTypes() []ChangeType {
+	changeTypes := c.ChangeTypes
+	if len(changeTypes) == 0 {
+		changeTypes = DefaultChangeTypes
+	}
+	changeTypes = slices.Clone(changeTypes)
+	slices.SortStableFunc(changeTypes, func(a, b ChangeType) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+	return changeTypes
+}
+
+// ChangeTypeIDs returns the IDs of the change types of the configuration, sorted by order.
+func (c *Config) ChangeTypeIDs() []string {
+	changeTypes := c.OrderedChangeTypes()
+	ids := make([]string, len(changeTypes))
+	for i, ct := range changeTypes {
+		ids[i] = ct.ID
+	}
+	return ids
+}
+
+func validateChangeTypes(changeTypes []ChangeType) error {
+	seen := make(map[string]bool, len(changeTypes))
+	for i, ct := range changeTypes {
+		switch {
+		case ct.ID == "":
+			return fmt.Errorf("'change_types' entry %d must specify an 'id'", i)
+		case seen[ct.ID]:
+			return fmt.Errorf("'change_types' contains id %q more than once", ct.ID)
+		case ct.Heading == "":
+			return fmt.Errorf("'change_types' entry %q must specify a 'heading'", ct.ID)
+		}
+		seen[ct.ID] = true
+	}
+	return nil
+}
+
 func makeAbs(rootDir, path, defaultPath string) string {
 	if path == "" {
 		return filepath.Clean(filepath.Join(rootDir, defaultPath))
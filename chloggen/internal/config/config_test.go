@@ -93,6 +93,49 @@ func TestNewFromFile(t *testing.T) {
 			},
 			expectErr: `contains key "fake" which is not defined in 'changelogs'`,
 		},
+		{
+			name: "change-types",
+			cfg: &Config{
+				ChangeTypes: []ChangeType{
+					{ID: "added", Heading: "Added", Order: 1},
+					{ID: "fixed", Heading: "Fixed", Emoji: "🐛", Order: 2},
+				},
+			},
+		},
+		{
+			name: "change-type-without-id",
+			cfg: &Config{
+				ChangeTypes: []ChangeType{{Heading: "Added"}},
+			},
+			expectErr: "'change_types' entry 0 must specify an 'id'",
+		},
+		{
+			name: "change-type-without-heading",
+			cfg: &Config{
+				ChangeTypes: []ChangeType{{ID: "added"}},
+			},
+			expectErr: `'change_types' entry "added" must specify a 'heading'`,
+		},
+		{
+			name: "duplicate-change-type",
+			cfg: &Config{
+				ChangeTypes: []ChangeType{{ID: "added", Heading: "Added"}, {ID: "added", Heading: "New"}},
+			},
+			expectErr: `'change_types' contains id "added" more than once`,
+		},
+		{
+			name: "repository",
+			cfg: &Config{
+				Repository: "open-telemetry/opentelemetry-go-build-tools",
+			},
+		},
+		{
+			name: "invalid-repository",
+			cfg: &Config{
+				Repository: "https://github.com/open-telemetry/opentelemetry-go-build-tools",
+			},
+			expectErr: "'repository' must be a GitHub repository as owner/repo",
+		},
 		{
 			name: "absolute-entries-dir",
 			cfg: &Config{
@@ -191,6 +234,21 @@ func TestNewFromFile(t *testing.T) {
 	}
 }
 
+func TestOrderedChangeTypes(t *testing.T) {
+	cfg := New("/tmp")
+	assert.Equal(t, DefaultChangeTypes, cfg.OrderedChangeTypes())
+	assert.Equal(t, []string{"breaking", "deprecation", "new_component", "enhancement", "bug_fix", "telemetry"}, cfg.ChangeTypeIDs())
+
+	cfg.ChangeTypes = []ChangeType{
+		{ID: "fixed", Heading: "Fixed", Order: 2},
+		{ID: "removed", Heading: "Removed", Order: 3},
+		{ID: "changed", Heading: "Changed", Order: 1},
+		{ID: "added", Heading: "Added", Order: 1},
+	}
+	assert.Equal(t, []string{"changed", "added", "fixed", "removed"}, cfg.ChangeTypeIDs())
+	assert.Equal(t, "fixed", cfg.ChangeTypes[0].ID, "the configuration must not be sorted in place")
+}
+
 func TestNewFromFileErr(t *testing.T) {
 	tempDir := t.TempDir()
 